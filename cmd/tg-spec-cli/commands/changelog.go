@@ -0,0 +1,60 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/superboomer/tg-spec-cli/internal/app"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	changelogSince      string
+	changelogFormat     string
+	changelogOutputPath string
+)
+
+var changelogCmd = &cobra.Command{
+	Use:   "changelog",
+	Short: "Print the API releases listed under 'Recent changes'",
+	Long: `Print the API releases listed under 'Recent changes', newest first.
+
+Each release has its version, date and the list of changes, with the anchors
+of the types and methods they link to. Use --since to list only a given release
+and the newer ones, e.g. '--since 7.0'.`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		log, syncLog, err := newLogger()
+		if err != nil {
+			fmt.Printf("failed to create logger: %v\n", err)
+			return
+		}
+		defer syncLog()
+
+		source, err := newSource(cmd, log)
+		if err != nil {
+			log.Fatal("invalid source options", zap.Error(err))
+		}
+		a := app.NewWithSource(log, source, "", typeFlag).WithOptions(sourceOptions())
+
+		ctx, stop := commandContext(cmd)
+		defer stop()
+
+		var buf bytes.Buffer
+		if err := a.Changelog(ctx, &buf, changelogSince, changelogFormat); err != nil {
+			fatalRunError(log, err)
+		}
+		if err := writeOutput(cmd.OutOrStdout(), changelogOutputPath, buf.Bytes()); err != nil {
+			log.Fatal("failed to write changelog", zap.Error(err))
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(changelogCmd)
+	changelogCmd.Flags().StringVar(&changelogSince, "since", "", "Only list this API version (e.g. 7.0) and newer releases")
+	changelogCmd.Flags().StringVarP(&changelogFormat, "format", "f", app.ChangelogMarkdown, "Output format: 'markdown' or 'json'")
+	changelogCmd.Flags().StringVarP(&changelogOutputPath, "output", "o", "-", "Output file for the changelog, or '-' for stdout")
+	addSourceFlags(changelogCmd)
+}
@@ -0,0 +1,27 @@
+package commands
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestChangelogCmdRun(t *testing.T) {
+	srv := pageServer(t)
+	logLevel = "silent"
+	url = srv.URL
+	typeFlag = "botapi"
+	changelogFormat = "markdown"
+	changelogOutputPath = "-"
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	changelogCmd.Run(cmd, []string{})
+
+	if !strings.HasPrefix(out.String(), "## Bot API 7.0") {
+		t.Errorf("unexpected changelog:\n%s", out.String())
+	}
+}
@@ -0,0 +1,86 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+
+	"github.com/superboomer/tg-spec-cli/internal/diff"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var (
+	diffFormat     string
+	diffOutputPath string
+)
+
+// exit ends the process; tests replace it to observe exit codes.
+var exit = os.Exit
+
+// Exit codes of the diff command. Errors get their own code so a CI job can
+// tell a broken comparison from a breaking API change.
+const (
+	exitBreaking = 1
+	exitError    = 2
+)
+
+var diffCmd = &cobra.Command{
+	Use:   "diff <old> <new>",
+	Short: "Compare two OpenAPI specs, intermediate models or documentation pages",
+	Long: `Compare two OpenAPI specs generated by this tool, or two intermediate models
+written by 'parse' or saved documentation pages (HTML), and report the added,
+removed and modified methods, parameters, types and fields. A page is parsed
+as 'parse' would, so a page can also be compared with a model.
+
+Removals, new required parameters, parameters that became required, fields
+that became optional and type changes are breaking. The command exits with
+status 1 when there is at least one breaking change, and with status 2 when
+the inputs can't be loaded or the report can't be written.`,
+	Args: cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		log, syncLog, err := newLogger()
+		if err != nil {
+			fmt.Printf("failed to create logger: %v\n", err)
+			return
+		}
+		defer syncLog()
+		fail := func(msg string, err error) {
+			log.Error(msg, zap.Error(err))
+			syncLog()
+			exit(exitError)
+		}
+
+		ctx, stop := commandContext(cmd)
+		defer stop()
+
+		before, after, err := diff.LoadPair(ctx, args[0], args[1])
+		if err != nil {
+			fail("failed to load inputs", err)
+			return
+		}
+		report := diff.Compare(before, after)
+
+		var buf bytes.Buffer
+		if err := report.Write(&buf, diffFormat); err != nil {
+			fail("failed to render report", err)
+			return
+		}
+		if err := writeOutput(cmd.OutOrStdout(), diffOutputPath, buf.Bytes()); err != nil {
+			fail("failed to write report", err)
+			return
+		}
+		if report.Breaking() {
+			syncLog()
+			exit(exitBreaking)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(diffCmd)
+	diffCmd.Flags().StringVarP(&diffFormat, "format", "f", diff.FormatText, "Report format: 'text', 'markdown' or 'json'")
+	diffCmd.Flags().StringVarP(&diffOutputPath, "output", "o", "-", "Output file for the report, or '-' for stdout")
+	diffCmd.Flags().StringVarP(&logLevel, "log-level", "l", "info", "Log level (debug, info, warn, error, fatal)")
+}
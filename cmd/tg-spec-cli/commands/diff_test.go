@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestDiffCmdRun(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	old := write("old.json", `{"schema_version": 2, "api_version": "9.0", "types": [], "methods": [
+		{"name": "getMe", "return_types": [{"name": "User"}]},
+		{"name": "sendGame", "return_types": [{"name": "Message"}]}]}`)
+	added := write("added.json", `{"schema_version": 2, "api_version": "9.1", "types": [], "methods": [
+		{"name": "getMe", "return_types": [{"name": "User"}]},
+		{"name": "sendGame", "return_types": [{"name": "Message"}]},
+		{"name": "sendChecklist", "return_types": [{"name": "Message"}]}]}`)
+	removed := write("removed.json", `{"schema_version": 2, "api_version": "9.1", "types": [], "methods": [
+		{"name": "getMe", "return_types": [{"name": "User"}]}]}`)
+
+	logLevel = "silent"
+	diffFormat = "text"
+	diffOutputPath = "-"
+	code := 0
+	exit = func(c int) { code = c }
+	defer func() { exit = os.Exit }()
+
+	tests := []struct {
+		name     string
+		after    string
+		wantCode int
+		want     string
+	}{
+		{name: "additions only", after: added, wantCode: 0, want: "added    method    sendChecklist"},
+		{name: "breaking", after: removed, wantCode: 1, want: "! removed  method    sendGame"},
+		{name: "missing input", after: filepath.Join(dir, "missing.json"), wantCode: 2},
+		{name: "unreadable input", after: write("garbage.json", "not a spec"), wantCode: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code = 0
+			var out bytes.Buffer
+			cmd := &cobra.Command{}
+			cmd.SetOut(&out)
+			diffCmd.Run(cmd, []string{old, tt.after})
+
+			if code != tt.wantCode {
+				t.Errorf("exit code = %d, want %d", code, tt.wantCode)
+			}
+			if !strings.Contains(out.String(), tt.want) {
+				t.Errorf("report does not contain %q:\n%s", tt.want, out.String())
+			}
+		})
+	}
+}
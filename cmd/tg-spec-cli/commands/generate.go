@@ -2,60 +2,73 @@ package commands
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/superboomer/tg-spec-cli/internal/app"
-	"github.com/superboomer/tg-spec-cli/internal/logger"
+	"github.com/superboomer/tg-spec-cli/internal/generator"
+	"github.com/superboomer/tg-spec-cli/internal/openapi"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
 
 var (
-	outputPath string
-	logLevel   string
-	url        string
-	typeFlag   string
+	outputPath   string
+	fromModel    string
+	httpMethods  []string
+	contentTypes []string
+	asOfVersion  string
+	outputFormat string
 )
 
 var generateCmd = &cobra.Command{
 	Use:   "generate",
 	Short: "Generate the OpenAPI specification",
 	Run: func(cmd *cobra.Command, _ []string) {
-		log, err := logger.New(logLevel)
+		log, syncLog, err := newLogger()
 		if err != nil {
 			fmt.Printf("failed to create logger: %v\n", err)
 			return
 		}
-		defer func(log *zap.Logger) {
-			// Syncing a console (stderr) on some platforms returns a harmless
-			// error ("inappropriate ioctl for device" on Linux, "bad file
-			// descriptor" on macOS); ignore those and only report real failures.
-			if err := log.Sync(); err != nil &&
-				!strings.Contains(err.Error(), "inappropriate ioctl for device") &&
-				!strings.Contains(err.Error(), "bad file descriptor") {
-				fmt.Printf("failed to sync logger: %v\n", err)
-			}
-		}(log)
+		defer syncLog()
+
+		genOpts, err := generator.ParseOptions(httpMethods, contentTypes)
+		if err != nil {
+			log.Fatal("invalid generator options", zap.Error(err))
+		}
 
-		// Set defaults for type
-		if typeFlag == "gateway" {
-			if !cmd.Flags().Changed("url") {
-				url = "https://core.telegram.org/gateway/api"
+		var a *app.App
+		if fromModel != "" {
+			a = app.NewFromModel(log, fromModel, outputPath)
+		} else {
+			source, err := newSource(cmd, log)
+			if err != nil {
+				log.Fatal("invalid source options", zap.Error(err))
 			}
+			a = app.NewWithSource(log, source, outputPath, typeFlag)
 		}
 
-		a := app.NewWithType(log, url, outputPath, typeFlag)
-		if err := a.Run(); err != nil {
-			log.Fatal("failed to run app", zap.Error(err))
+		opts := sourceOptions()
+		opts.Generator = genOpts
+		opts.AsOfVersion = asOfVersion
+		opts.Format = outputFormat
+		a.WithOptions(opts)
+
+		ctx, stop := commandContext(cmd)
+		defer stop()
+
+		if err := a.Run(ctx); err != nil {
+			fatalRunError(log, err)
 		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(generateCmd)
-	generateCmd.Flags().StringVarP(&outputPath, "output", "o", ".", "Output path for the OpenAPI specification. You can specify a directory or a full file path. If the path contains '%v', it will be replaced with the API version (e.g., './specs/bot-api-%v.json'). If a directory does not exist, it will be created automatically.")
-	generateCmd.Flags().StringVarP(&logLevel, "log-level", "l", "info", "Log level (debug, info, warn, error, fatal)")
-	generateCmd.Flags().StringVarP(&url, "url", "u", "https://core.telegram.org/bots/api", "URL of the Telegram Bot API documentation")
-	generateCmd.Flags().StringVarP(&typeFlag, "type", "t", "botapi", "API type: 'botapi' (default) or 'gateway'. For 'gateway', uses https://core.telegram.org/gateway/api and different OpenAPI info/auth.")
+	generateCmd.Flags().StringVarP(&outputPath, "output", "o", ".", "Output path for the OpenAPI specification. You can specify a directory or a full file path. If the path contains '%v', it will be replaced with the API version (e.g., './specs/bot-api-%v.json'). Directories get openapi-v<version>.json, or .yaml with --format yaml. If a directory does not exist, it will be created automatically.")
+	generateCmd.Flags().StringVar(&fromModel, "from-model", "", "Generate from an intermediate model written by 'parse' instead of scraping the documentation")
+	generateCmd.Flags().StringSliceVar(&httpMethods, "methods", []string{"post"}, "HTTP methods to expose every API method on: get, post. GET operations take their parameters from the query string")
+	generateCmd.Flags().StringSliceVar(&contentTypes, "content-types", nil, "Request body content types of POST operations: json, form (application/x-www-form-urlencoded), multipart. By default JSON, plus multipart for methods that upload files")
+	generateCmd.Flags().StringVar(&asOfVersion, "as-of-version", "", "Generate the spec as it stood at this API version (e.g. 7.10), dropping the methods, types, fields and parameters added by later releases under 'Recent changes'")
+	generateCmd.Flags().StringVarP(&outputFormat, "format", "f", openapi.FormatJSON, "Output format: 'json' or 'yaml'")
+	addSourceFlags(generateCmd)
 }
@@ -1,19 +1,25 @@
 package commands
 
 import (
+	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/spf13/cobra"
 )
 
 // fakeBotAPIPage is a minimal documentation page sufficient to drive a full
-// generate run (version marker + one type + one method).
+// generate run (a release + one type + one method).
 const fakeBotAPIPage = `<!DOCTYPE html><html><body>
-	<strong>Bot API 7.0</strong>
+	<h3>Recent changes</h3>
+	<h4>December 29, 2023</h4>
+	<p><strong>Bot API 7.0</strong></p>
+	<ul><li>Added the method <a href="#getme">getMe</a>.</li></ul>
+	<h3>Available types</h3>
 	<h4>User</h4>
 	<p>This object represents a user.</p>
 	<table>
@@ -30,6 +36,9 @@ func pageServer(t *testing.T) *httptest.Server {
 		_, _ = w.Write([]byte(fakeBotAPIPage))
 	}))
 	t.Cleanup(srv.Close)
+	// Keep the documentation cache out of the user's home directory.
+	cacheDir = t.TempDir()
+	t.Cleanup(func() { cacheDir = "" })
 	return srv
 }
 
@@ -95,3 +104,130 @@ func TestGenerateCmdRun_InvalidLogLevel(t *testing.T) {
 	}()
 	generateCmd.Run(&cobra.Command{}, []string{})
 }
+
+func TestGenerateCmdRun_InputFile(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "api.html")
+	if err := os.WriteFile(input, []byte(fakeBotAPIPage), 0600); err != nil {
+		t.Fatal(err)
+	}
+	outputPath = filepath.Join(dir, "spec-%v.json")
+	logLevel = "info"
+	url = "http://127.0.0.1:0" // must not be contacted
+	typeFlag = "botapi"
+	inputPath = input
+	defer func() { inputPath = "" }()
+
+	generateCmd.Run(&cobra.Command{}, []string{})
+
+	if _, err := os.Stat(filepath.Join(dir, "spec-7.0.json")); err != nil {
+		t.Errorf("expected generated spec file: %v", err)
+	}
+}
+
+func TestGenerateCmdRun_InputStdin(t *testing.T) {
+	dir := t.TempDir()
+	outputPath = filepath.Join(dir, "spec-%v.json")
+	logLevel = "info"
+	url = "http://127.0.0.1:0"
+	typeFlag = "botapi"
+	inputPath = "-"
+	defer func() { inputPath = "" }()
+
+	cmd := &cobra.Command{}
+	cmd.SetIn(strings.NewReader(fakeBotAPIPage))
+	generateCmd.Run(cmd, []string{})
+
+	if _, err := os.Stat(filepath.Join(dir, "spec-7.0.json")); err != nil {
+		t.Errorf("expected generated spec file: %v", err)
+	}
+}
+
+func TestGenerateCmdRun_FromModel(t *testing.T) {
+	dir := t.TempDir()
+	modelPath := filepath.Join(dir, "model.json")
+	model := `{"schema_version": 2, "api_type": "botapi", "api_version": "7.1", "types": [], "methods": [{"name": "getMe", "return_types": [{"name": "boolean"}]}]}`
+	if err := os.WriteFile(modelPath, []byte(model), 0600); err != nil {
+		t.Fatal(err)
+	}
+	outputPath = filepath.Join(dir, "spec-%v.json")
+	logLevel = "info"
+	fromModel = modelPath
+	defer func() { fromModel = "" }()
+
+	generateCmd.Run(&cobra.Command{}, []string{})
+
+	if _, err := os.Stat(filepath.Join(dir, "spec-7.1.json")); err != nil {
+		t.Errorf("expected spec generated from model: %v", err)
+	}
+}
+
+func TestGenerateCmdRun_MethodsAndContentTypes(t *testing.T) {
+	dir := t.TempDir()
+	modelPath := filepath.Join(dir, "model.json")
+	model := `{"schema_version": 2, "api_type": "botapi", "api_version": "7.1", "types": [], "methods": [{"name": "getMe", "return_types": [{"name": "boolean"}]}]}`
+	if err := os.WriteFile(modelPath, []byte(model), 0600); err != nil {
+		t.Fatal(err)
+	}
+	outputPath = filepath.Join(dir, "spec.json")
+	logLevel = "info"
+	fromModel = modelPath
+	httpMethods = []string{"get", "post"}
+	contentTypes = []string{"form"}
+	defer func() {
+		fromModel = ""
+		httpMethods = []string{"post"}
+		contentTypes = nil
+	}()
+
+	generateCmd.Run(&cobra.Command{}, []string{})
+
+	data, err := os.ReadFile(filepath.Join(dir, "spec.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var spec struct {
+		Paths map[string]map[string]struct {
+			RequestBody struct {
+				Content map[string]json.RawMessage `json:"content"`
+			} `json:"requestBody"`
+		} `json:"paths"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatal(err)
+	}
+	getMe := spec.Paths["/getMe"]
+	if _, ok := getMe["get"]; !ok {
+		t.Error("expected a GET operation")
+	}
+	if _, ok := getMe["post"].RequestBody.Content["application/x-www-form-urlencoded"]; !ok {
+		t.Errorf("expected a form-urlencoded POST body, got %v", getMe["post"].RequestBody.Content)
+	}
+}
+
+func TestGenerateCmdRun_FormatYAML(t *testing.T) {
+	dir := t.TempDir()
+	modelPath := filepath.Join(dir, "model.json")
+	model := `{"schema_version": 2, "api_type": "botapi", "api_version": "7.1", "types": [], "methods": [{"name": "getMe", "return_types": [{"name": "boolean"}]}]}`
+	if err := os.WriteFile(modelPath, []byte(model), 0600); err != nil {
+		t.Fatal(err)
+	}
+	outputPath = dir
+	logLevel = "info"
+	fromModel = modelPath
+	outputFormat = "yaml"
+	defer func() {
+		fromModel = ""
+		outputFormat = "json"
+	}()
+
+	generateCmd.Run(&cobra.Command{}, []string{})
+
+	data, err := os.ReadFile(filepath.Join(dir, "openapi-v7.1.yaml"))
+	if err != nil {
+		t.Fatalf("expected a YAML spec: %v", err)
+	}
+	if !strings.HasPrefix(string(data), "openapi: 3.1.0\ninfo:\n") || !strings.Contains(string(data), "\n  /getMe:\n") {
+		t.Errorf("unexpected YAML spec:\n%s", data)
+	}
+}
@@ -0,0 +1,66 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+
+	"github.com/superboomer/tg-spec-cli/internal/app"
+	"github.com/superboomer/tg-spec-cli/internal/fsutil"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+var modelOutputPath string
+
+var parseCmd = &cobra.Command{
+	Use:   "parse",
+	Short: "Parse the documentation and dump the intermediate model as JSON",
+	Long: `Parse the documentation and dump the intermediate model as JSON.
+
+The model holds the types and methods exactly as the parser understood them,
+before any OpenAPI conversion. It can be fed back into 'generate --from-model'.`,
+	Run: func(cmd *cobra.Command, _ []string) {
+		log, syncLog, err := newLogger()
+		if err != nil {
+			fmt.Printf("failed to create logger: %v\n", err)
+			return
+		}
+		defer syncLog()
+
+		source, err := newSource(cmd, log)
+		if err != nil {
+			log.Fatal("invalid source options", zap.Error(err))
+		}
+		a := app.NewWithSource(log, source, "", typeFlag).WithOptions(sourceOptions())
+
+		ctx, stop := commandContext(cmd)
+		defer stop()
+
+		// Buffer the model so a failed run never leaves a partial file.
+		var buf bytes.Buffer
+		if err := a.Parse(ctx, &buf); err != nil {
+			fatalRunError(log, err)
+		}
+		if err := writeOutput(cmd.OutOrStdout(), modelOutputPath, buf.Bytes()); err != nil {
+			log.Fatal("failed to write model", zap.Error(err))
+		}
+	},
+}
+
+// writeOutput writes data to path, or to stdout when path is "-". Files are
+// replaced atomically, so a failed run never leaves a partial file behind.
+func writeOutput(stdout io.Writer, path string, data []byte) error {
+	if path == "-" {
+		_, err := stdout.Write(data)
+		return err
+	}
+	return fsutil.WriteFileAtomic(path, data)
+}
+
+func init() {
+	rootCmd.AddCommand(parseCmd)
+	parseCmd.Flags().StringVarP(&modelOutputPath, "output", "o", "-", "Output file for the model, or '-' for stdout")
+	addSourceFlags(parseCmd)
+}
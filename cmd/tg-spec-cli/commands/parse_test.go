@@ -0,0 +1,82 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+func TestParseCmd(t *testing.T) {
+	if parseCmd.Use != "parse" {
+		t.Errorf("parseCmd.Use = %v, want 'parse'", parseCmd.Use)
+	}
+	if parseCmd.Short == "" {
+		t.Error("parseCmd.Short should not be empty")
+	}
+}
+
+func TestParseCmdRun_Stdout(t *testing.T) {
+	srv := pageServer(t)
+	logLevel = "silent"
+	url = srv.URL
+	typeFlag = "botapi"
+	modelOutputPath = "-"
+
+	var out bytes.Buffer
+	cmd := &cobra.Command{}
+	cmd.SetOut(&out)
+	parseCmd.Run(cmd, []string{})
+
+	var model map[string]any
+	if err := json.Unmarshal(out.Bytes(), &model); err != nil {
+		t.Fatalf("parse output is not JSON: %v\n%s", err, out.String())
+	}
+	if model["api_version"] != "7.0" {
+		t.Errorf("api_version = %v, want 7.0", model["api_version"])
+	}
+}
+
+func TestParseCmdRun_File(t *testing.T) {
+	srv := pageServer(t)
+	logLevel = "silent"
+	url = srv.URL
+	typeFlag = "botapi"
+	modelOutputPath = filepath.Join(t.TempDir(), "model.json")
+	defer func() { modelOutputPath = "-" }()
+
+	parseCmd.Run(&cobra.Command{}, []string{})
+
+	if _, err := os.Stat(modelOutputPath); err != nil {
+		t.Errorf("expected model file: %v", err)
+	}
+}
+
+func TestWriteOutput(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "model.json")
+	if err := os.WriteFile(path, []byte("previous, longer content"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := writeOutput(nil, path, []byte("new")); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "new" {
+		t.Errorf("file = %q, want %q", got, "new")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("expected only the output file, got %d entries", len(entries))
+	}
+
+	if err := writeOutput(nil, filepath.Join(dir, "missing", "model.json"), []byte("new")); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+
+	var stdout bytes.Buffer
+	if err := writeOutput(&stdout, "-", []byte("new")); err != nil || stdout.String() != "new" {
+		t.Errorf("writeOutput(-) = %q, %v", stdout.String(), err)
+	}
+}
@@ -0,0 +1,177 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/superboomer/tg-spec-cli/internal/app"
+	"github.com/superboomer/tg-spec-cli/internal/logger"
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+// Flags shared by every command that reads the documentation.
+var (
+	logLevel   string
+	url        string
+	typeFlag   string
+	inputPath  string
+	cacheDir   string
+	noCache    bool
+	offline    bool
+	retries    int
+	retryDelay time.Duration
+	timeout    time.Duration
+
+	descriptionFormat string
+	docsURL           string
+)
+
+// addSourceFlags registers the flags that control where the documentation is
+// read from and how the run behaves.
+func addSourceFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&logLevel, "log-level", "l", "info", "Log level (debug, info, warn, error, fatal)")
+	cmd.Flags().StringVarP(&url, "url", "u", "https://core.telegram.org/bots/api", "URL of the Telegram Bot API documentation")
+	cmd.Flags().StringVarP(&typeFlag, "type", "t", "botapi", "API type: 'botapi' (default) or 'gateway'. For 'gateway', uses https://core.telegram.org/gateway/api and different OpenAPI info/auth.")
+	cmd.Flags().StringVarP(&inputPath, "input", "i", "", "Read the documentation HTML from a local file instead of the URL. Use '-' to read from stdin.")
+	cmd.Flags().StringVar(&cacheDir, "cache-dir", "", "Directory for the documentation cache (default: the user cache directory, e.g. ~/.cache/tg-spec-cli)")
+	cmd.Flags().BoolVar(&noCache, "no-cache", false, "Always download the documentation and don't read or write the cache")
+	cmd.Flags().BoolVar(&offline, "offline", false, "Only use the cached documentation and never touch the network")
+	cmd.Flags().IntVar(&retries, "retries", telegram.DefaultRetryPolicy.MaxRetries, "Number of times to retry fetching the documentation on network errors, 429 and 5xx responses")
+	cmd.Flags().DurationVar(&timeout, "timeout", 0, "Abort the whole run after this duration (e.g. 2m); 0 disables the timeout")
+	cmd.Flags().DurationVar(&retryDelay, "retry-delay", telegram.DefaultRetryPolicy.BaseDelay, "Initial delay between retries; doubled on each attempt (with jitter) unless the server sends Retry-After")
+	cmd.Flags().StringVar(&descriptionFormat, "description-format", telegram.DescriptionPlain, "Format of descriptions: 'plain' text or 'markdown' (CommonMark, with links made absolute)")
+	cmd.Flags().StringVar(&docsURL, "docs-url", "", "Public documentation page that Markdown links and externalDocs point to (default: the official page of --type, whatever --url is)")
+}
+
+// sourceOptions returns the app options derived from the source flags. Links
+// point to --docs-url, or the official page of the API type: --url may be a
+// local mirror whose host must not leak into the output.
+func sourceOptions() app.Options {
+	docs := docsURL
+	if docs == "" {
+		docs = telegram.DefaultDocsURL
+		if typeFlag == "gateway" {
+			docs = telegram.GatewayDocsURL
+		}
+	}
+	return app.Options{
+		DescriptionFormat: descriptionFormat,
+		DocsURL:           docs,
+	}
+}
+
+// newLogger creates the logger for a command run. The returned function syncs
+// it and must be deferred.
+func newLogger() (*zap.Logger, func(), error) {
+	log, err := logger.New(logLevel)
+	if err != nil {
+		return nil, nil, err
+	}
+	return log, func() {
+		// Syncing a console (stderr) on some platforms returns a harmless
+		// error ("inappropriate ioctl for device" on Linux, "bad file
+		// descriptor" on macOS); ignore those and only report real failures.
+		if err := log.Sync(); err != nil &&
+			!strings.Contains(err.Error(), "inappropriate ioctl for device") &&
+			!strings.Contains(err.Error(), "bad file descriptor") {
+			fmt.Printf("failed to sync logger: %v\n", err)
+		}
+	}, nil
+}
+
+// commandContext derives the context for a run from the command's context: it
+// is cancelled on SIGINT/SIGTERM and, when --timeout is set, after the timeout.
+func commandContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	ctx := cmd.Context()
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	if timeout <= 0 {
+		return ctx, stop
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	return ctx, func() {
+		cancel()
+		stop()
+	}
+}
+
+// newSource picks where the documentation HTML is read from: the --input file,
+// standard input for "-", or the --url otherwise (through the on-disk cache
+// unless --no-cache is given). The cache flags only apply to the URL and are
+// rejected together with --input. Cache problems are logged to log.
+func newSource(cmd *cobra.Command, log *zap.Logger) (telegram.Source, error) {
+	// Set defaults for type
+	if typeFlag == "gateway" {
+		if !cmd.Flags().Changed("url") {
+			url = telegram.GatewayDocsURL
+		}
+	}
+
+	if inputPath != "" {
+		switch {
+		case cacheDir != "":
+			return nil, fmt.Errorf("--cache-dir cannot be combined with --input")
+		case noCache:
+			return nil, fmt.Errorf("--no-cache cannot be combined with --input")
+		case offline:
+			return nil, fmt.Errorf("--offline cannot be combined with --input")
+		}
+	}
+
+	switch inputPath {
+	case "":
+	case "-":
+		return &telegram.ReaderSource{Name: "stdin", Reader: cmd.InOrStdin()}, nil
+	default:
+		return &telegram.FileSource{Path: inputPath}, nil
+	}
+
+	retry := telegram.DefaultRetryPolicy
+	retry.MaxRetries = retries
+	retry.BaseDelay = retryDelay
+
+	if noCache {
+		if offline {
+			return nil, fmt.Errorf("--offline cannot be combined with --no-cache")
+		}
+		return &telegram.HTTPSource{URL: url, Retry: retry, Log: log}, nil
+	}
+
+	dir := cacheDir
+	if dir == "" {
+		var err error
+		if dir, err = telegram.DefaultCacheDir(); err != nil {
+			return nil, err
+		}
+	}
+	return &telegram.HTTPSource{URL: url, Cache: &telegram.Cache{Dir: dir}, Offline: offline, Retry: retry, Log: log}, nil
+}
+
+// fatalRunError logs err with a message that tells the user what kind of
+// failure happened and exits.
+func fatalRunError(log *zap.Logger, err error) {
+	var fetchErr *telegram.FetchError
+	switch {
+	case errors.Is(err, context.Canceled):
+		log.Fatal("interrupted", zap.Error(err))
+	case errors.Is(err, context.DeadlineExceeded):
+		log.Fatal("timed out", zap.Duration("timeout", timeout), zap.Error(err))
+	case errors.As(err, &fetchErr):
+		log.Fatal("documentation unreachable", zap.String("url", fetchErr.URL), zap.Int("status", fetchErr.StatusCode), zap.Error(err))
+	case errors.Is(err, telegram.ErrLayoutChanged):
+		log.Fatal("documentation layout changed, the parser needs updating", zap.Error(err))
+	default:
+		log.Fatal("failed to run app", zap.Error(err))
+	}
+}
@@ -0,0 +1,100 @@
+package commands
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func TestNewSource(t *testing.T) {
+	defer func() { inputPath, cacheDir, noCache, offline = "", "", false, false }()
+	url = "https://core.telegram.org/bots/api"
+
+	inputPath, cacheDir, noCache, offline = "", t.TempDir(), false, true
+	src, err := newSource(&cobra.Command{}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	hs, ok := src.(*telegram.HTTPSource)
+	if !ok || hs.Cache == nil || hs.Cache.Dir != cacheDir || !hs.Offline {
+		t.Errorf("expected cached offline HTTP source, got %+v", src)
+	}
+
+	noCache = true
+	if _, err := newSource(&cobra.Command{}, zap.NewNop()); err == nil {
+		t.Error("--offline with --no-cache should be rejected")
+	}
+
+	offline = false
+	src, err = newSource(&cobra.Command{}, zap.NewNop())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if hs, ok := src.(*telegram.HTTPSource); !ok || hs.Cache != nil {
+		t.Errorf("--no-cache should produce an uncached HTTP source, got %+v", src)
+	}
+}
+
+func TestNewSource_InputRejectsCacheFlags(t *testing.T) {
+	defer func() { inputPath, cacheDir, noCache, offline = "", "", false, false }()
+
+	for _, tt := range []struct {
+		name             string
+		cacheDir         string
+		noCache, offline bool
+	}{
+		{name: "cache-dir", cacheDir: t.TempDir()},
+		{name: "no-cache", noCache: true},
+		{name: "offline", offline: true},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			inputPath, cacheDir, noCache, offline = "api.html", tt.cacheDir, tt.noCache, tt.offline
+			if _, err := newSource(&cobra.Command{}, zap.NewNop()); err == nil {
+				t.Errorf("--%s with --input should be rejected", tt.name)
+			}
+		})
+	}
+
+	inputPath, cacheDir, noCache, offline = "api.html", "", false, false
+	if src, err := newSource(&cobra.Command{}, zap.NewNop()); err != nil {
+		t.Errorf("--input alone: %v", err)
+	} else if _, ok := src.(*telegram.FileSource); !ok {
+		t.Errorf("--input should produce a file source, got %+v", src)
+	}
+}
+
+func TestSourceOptions_DocsURL(t *testing.T) {
+	oldURL, oldType := url, typeFlag
+	defer func() { url, typeFlag, docsURL = oldURL, oldType, "" }()
+
+	for _, tt := range []struct {
+		typeFlag, docsURL, want string
+	}{
+		{"botapi", "", telegram.DefaultDocsURL},
+		{"gateway", "", telegram.GatewayDocsURL},
+		{"botapi", "https://docs.example.com/api", "https://docs.example.com/api"},
+	} {
+		url, typeFlag, docsURL = "http://mirror.local/api", tt.typeFlag, tt.docsURL
+		if got := sourceOptions().DocsURL; got != tt.want {
+			t.Errorf("DocsURL(type=%s, docs-url=%q) = %s, want %s", tt.typeFlag, tt.docsURL, got, tt.want)
+		}
+	}
+}
+
+func TestCommandContext_Timeout(t *testing.T) {
+	timeout = time.Millisecond
+	defer func() { timeout = 0 }()
+
+	ctx, stop := commandContext(&cobra.Command{})
+	defer stop()
+	<-ctx.Done()
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		t.Errorf("expected deadline exceeded, got %v", ctx.Err())
+	}
+}
@@ -1,9 +1,14 @@
 package app
 
 import (
+	"context"
 	"fmt"
+	"io"
+	"os"
 
 	"github.com/superboomer/tg-spec-cli/internal/generator"
+	"github.com/superboomer/tg-spec-cli/internal/model"
+	"github.com/superboomer/tg-spec-cli/internal/openapi"
 	"github.com/superboomer/tg-spec-cli/internal/telegram"
 
 	"go.uber.org/zap"
@@ -11,81 +16,239 @@ import (
 
 type App struct {
 	log        *zap.Logger
-	url        string
+	source     telegram.Source
+	modelPath  string
 	outputPath string
 	typeFlag   string
+	opts       Options
+}
+
+// Options tune parsing and the generated specification.
+type Options struct {
+	// Generator selects the HTTP methods and request content types.
+	Generator generator.Options
+	// DescriptionFormat is telegram.DescriptionPlain (the default) or
+	// telegram.DescriptionMarkdown.
+	DescriptionFormat string
+	// DocsURL is the documentation page links in Markdown descriptions are
+	// resolved against. Defaults to telegram.DefaultDocsURL.
+	DocsURL string
+	// AsOfVersion, when set, makes Run generate the specification as it
+	// stood at that API version, see model.Model.AsOf.
+	AsOfVersion string
+	// Format is the format of the saved specification, openapi.FormatJSON
+	// (the default) or openapi.FormatYAML.
+	Format string
 }
 
 func NewWithType(log *zap.Logger, url, outputPath, typeFlag string) *App {
+	return NewWithSource(log, &telegram.HTTPSource{URL: url}, outputPath, typeFlag)
+}
+
+// NewWithSource creates an App that reads the documentation page from source
+// instead of fetching it from a URL.
+func NewWithSource(log *zap.Logger, source telegram.Source, outputPath, typeFlag string) *App {
 	return &App{
 		log:        log,
-		url:        url,
+		source:     source,
 		outputPath: outputPath,
 		typeFlag:   typeFlag,
 	}
 }
 
-func (a *App) Run() error {
+// NewFromModel creates an App that generates the spec from a previously
+// exported intermediate model instead of scraping the documentation. The API
+// type is taken from the model.
+func NewFromModel(log *zap.Logger, modelPath, outputPath string) *App {
+	return &App{
+		log:        log,
+		modelPath:  modelPath,
+		outputPath: outputPath,
+	}
+}
+
+// WithOptions sets the parsing and generation options and returns a.
+func (a *App) WithOptions(opts Options) *App {
+	a.opts = opts
+	return a
+}
+
+// Run loads the model, generates the OpenAPI spec and saves it. Cancelling
+// ctx aborts the fetch, parsing and generation; the output
+// file is written atomically, so a cancelled run never leaves a partial spec
+// behind.
+func (a *App) Run(ctx context.Context) error {
 	a.log.Info("starting app")
 
-	if a.typeFlag != "botapi" && a.typeFlag != "gateway" {
-		a.log.Error("unsupported API type", zap.String("type", a.typeFlag))
-		return fmt.Errorf("unsupported API type: %s", a.typeFlag)
+	switch a.opts.Format {
+	case "", openapi.FormatJSON, openapi.FormatYAML:
+	default:
+		return fmt.Errorf("unsupported output format: %s", a.opts.Format)
 	}
 
-	a.log.Debug("fetching Telegram API page", zap.String("url", a.url), zap.String("type", a.typeFlag))
-
-	page, err := telegram.GetPage(a.url)
+	m, err := a.load(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get page: %w", err)
+		return err
 	}
-	a.log.Debug("successfully fetched page")
 
-	version, err := page.GetVersion()
+	if a.opts.AsOfVersion != "" {
+		if m, err = a.asOf(m, a.opts.AsOfVersion); err != nil {
+			return err
+		}
+	}
+
+	gen := generator.NewWithType(a.log, m.APIVersion, m.TypeMap(), m.Methods, m.APIType).
+		WithOptions(a.opts.Generator).
+		WithSections(m.Sections).
+		WithDocsURL(m.DocsURL).
+		WithFormat(a.opts.Format)
+	a.log.Debug("generating OpenAPI schema")
+	openAPI, err := gen.Generate(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get version: %w", err)
+		return fmt.Errorf("failed to generate openapi: %w", err)
+	}
+	a.log.Debug("OpenAPI schema generated")
+
+	a.log.Debug("saving OpenAPI schema", zap.String("outputPath", a.outputPath))
+	if err := gen.Save(ctx, openAPI, a.outputPath); err != nil {
+		return fmt.Errorf("failed to save openapi: %w", err)
 	}
-	a.log.Info("got version", zap.String("version", version))
 
-	types, err := page.GetTypes()
+	a.log.Info("finished app")
+	return nil
+}
+
+// asOf returns m as it stood at version. The release history must reach back
+// to version for the result to be exact; older versions are still accepted
+// but only strip what the listed releases introduced.
+func (a *App) asOf(m *model.Model, version string) (*model.Model, error) {
+	if telegram.CompareVersions(version, m.APIVersion) > 0 {
+		return nil, fmt.Errorf("version %s is newer than the documented version %s", version, m.APIVersion)
+	}
+	if len(m.Releases) == 0 {
+		return nil, fmt.Errorf("can't build the spec as of version %s: the documentation lists no releases", version)
+	}
+	if oldest := m.Releases[len(m.Releases)-1].Version; telegram.CompareVersions(version, oldest) < 0 {
+		a.log.Warn("version predates the oldest listed release; elements added in between are kept",
+			zap.String("version", version), zap.String("oldest_release", oldest))
+	}
+	a.log.Info("generating historical spec", zap.String("version", version))
+	return m.AsOf(version), nil
+}
+
+// Parse scrapes the documentation and writes the intermediate model to w as
+// JSON.
+func (a *App) Parse(ctx context.Context, w io.Writer) error {
+	a.log.Info("starting app")
+
+	m, err := a.load(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get types: %w", err)
+		return err
 	}
-	a.log.Info("got types", zap.Int("count", len(types)))
-	if a.log.Core().Enabled(zap.DebugLevel) {
-		typeNames := make([]string, 0, len(types))
-		for name := range types {
-			typeNames = append(typeNames, name)
-		}
-		a.log.Debug("type names", zap.Strings("types", typeNames))
+
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := m.Write(w); err != nil {
+		return fmt.Errorf("failed to write model: %w", err)
+	}
+
+	a.log.Info("finished app")
+	return nil
+}
+
+// load returns the intermediate model, either read from the model file or
+// parsed from the documentation source.
+func (a *App) load(ctx context.Context) (*model.Model, error) {
+	if a.modelPath != "" {
+		return a.readModel()
 	}
 
-	methods, err := page.GetMethods()
+	if a.typeFlag != "botapi" && a.typeFlag != "gateway" {
+		a.log.Error("unsupported API type", zap.String("type", a.typeFlag))
+		return nil, fmt.Errorf("unsupported API type: %s", a.typeFlag)
+	}
+	switch a.opts.DescriptionFormat {
+	case "", telegram.DescriptionPlain, telegram.DescriptionMarkdown:
+	default:
+		return nil, fmt.Errorf("unsupported description format: %s", a.opts.DescriptionFormat)
+	}
+
+	a.log.Debug("loading Telegram API page", zap.Stringer("source", a.source), zap.String("type", a.typeFlag))
+
+	page, err := telegram.LoadPage(ctx, a.source)
 	if err != nil {
-		return fmt.Errorf("failed to get methods: %w", err)
+		return nil, fmt.Errorf("failed to get page: %w", err)
 	}
-	a.log.Info("got methods", zap.Int("count", len(methods)))
+	a.log.Debug("successfully loaded page")
+	page.DescriptionFormat = a.opts.DescriptionFormat
+	page.BaseURL = a.opts.DocsURL
+
+	m, err := model.FromPage(ctx, a.typeFlag, page)
+	if err != nil {
+		return nil, err
+	}
+	a.log.Info("parsed page",
+		zap.String("version", m.APIVersion),
+		zap.Int("types", len(m.Types)),
+		zap.Int("methods", len(m.Methods)),
+		zap.Int("releases", len(m.Releases)))
 	if a.log.Core().Enabled(zap.DebugLevel) {
-		methodNames := make([]string, 0, len(methods))
-		for _, m := range methods {
-			methodNames = append(methodNames, m.Name)
+		typeNames := make([]string, 0, len(m.Types))
+		for _, t := range m.Types {
+			typeNames = append(typeNames, t.Name)
+		}
+		a.log.Debug("type names", zap.Strings("types", typeNames))
+		methodNames := make([]string, 0, len(m.Methods))
+		for _, method := range m.Methods {
+			methodNames = append(methodNames, method.Name)
 		}
 		a.log.Debug("method names", zap.Strings("methods", methodNames))
 	}
+	if m.Report != nil {
+		a.logReport(m.Report)
+	}
+	return m, nil
+}
 
-	gen := generator.NewWithType(a.log, version, types, methods, a.typeFlag)
-	a.log.Debug("generating OpenAPI schema")
-	openAPI, err := gen.Generate()
-	if err != nil {
-		return fmt.Errorf("failed to generate openapi: %w", err)
+// maxReportedPhrases caps the unparsed phrases listed in the summary
+// warning; the rest are logged at debug level and kept in the model.
+const maxReportedPhrases = 10
+
+// logReport warns about the constraint phrases the parser didn't understand,
+// listing the first few as "element: phrase" so they show up on stderr
+// without raising the log level.
+func (a *App) logReport(report *telegram.Report) {
+	unparsed := report.UnparsedConstraints
+	summary := make([]string, 0, min(len(unparsed), maxReportedPhrases))
+	for i, u := range unparsed {
+		if i < maxReportedPhrases {
+			summary = append(summary, u.Element+": "+u.Phrase)
+			continue
+		}
+		a.log.Debug("unparsed constraint", zap.String("element", u.Element), zap.String("phrase", u.Phrase))
 	}
-	a.log.Debug("OpenAPI schema generated")
+	a.log.Warn("some constraint phrases were not understood; the model's report lists them all",
+		zap.Int("count", len(unparsed)), zap.Strings("phrases", summary))
+}
 
-	a.log.Debug("saving OpenAPI schema", zap.String("outputPath", a.outputPath))
-	if err := gen.Save(openAPI, a.outputPath); err != nil {
-		return fmt.Errorf("failed to save openapi: %w", err)
+func (a *App) readModel() (*model.Model, error) {
+	a.log.Debug("reading intermediate model", zap.String("path", a.modelPath))
+
+	f, err := os.Open(a.modelPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open model: %w", err)
 	}
+	defer f.Close()
 
-	a.log.Info("finished app")
-	return nil
+	m, err := model.Read(f)
+	if err != nil {
+		return nil, err
+	}
+	a.log.Info("read model",
+		zap.String("type", m.APIType),
+		zap.String("version", m.APIVersion),
+		zap.Int("types", len(m.Types)),
+		zap.Int("methods", len(m.Methods)))
+	return m, nil
 }
@@ -1,13 +1,18 @@
 package app
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+
 	"go.uber.org/zap"
 )
 
@@ -62,7 +67,7 @@ func TestApp_Run_Success(t *testing.T) {
 	out := filepath.Join(t.TempDir(), "spec-%v.json")
 
 	a := NewWithType(zap.NewNop(), srv.URL, out, "botapi")
-	if err := a.Run(); err != nil {
+	if err := a.Run(context.Background()); err != nil {
 		t.Fatalf("Run() error = %v", err)
 	}
 
@@ -94,14 +99,14 @@ func TestApp_Run_DebugLogging(t *testing.T) {
 		t.Fatal(err)
 	}
 	a := NewWithType(log, srv.URL, out, "botapi")
-	if err := a.Run(); err != nil {
+	if err := a.Run(context.Background()); err != nil {
 		t.Fatalf("Run() error = %v", err)
 	}
 }
 
 func TestApp_Run_UnreachableURL(t *testing.T) {
 	a := NewWithType(zap.NewNop(), "http://127.0.0.1:0", "out.json", "botapi")
-	if err := a.Run(); err == nil {
+	if err := a.Run(context.Background()); err == nil {
 		t.Error("expected error for unreachable URL")
 	}
 }
@@ -110,7 +115,7 @@ func TestApp_Run_VersionError(t *testing.T) {
 	// Page without any version markers -> GetVersion fails.
 	srv := newPageServer(t, `<!DOCTYPE html><html><body><p>nothing</p></body></html>`)
 	a := NewWithType(zap.NewNop(), srv.URL, "out.json", "botapi")
-	if err := a.Run(); err == nil {
+	if err := a.Run(context.Background()); err == nil {
 		t.Error("expected error when version cannot be determined")
 	}
 }
@@ -123,7 +128,114 @@ func TestApp_Run_SaveError(t *testing.T) {
 		t.Fatal(err)
 	}
 	a := NewWithType(zap.NewNop(), srv.URL, filepath.Join(blocker, "sub", "out.json"), "botapi")
-	if err := a.Run(); err == nil {
+	if err := a.Run(context.Background()); err == nil {
 		t.Error("expected error when output cannot be saved")
 	}
 }
+
+func TestApp_Run_FileSource(t *testing.T) {
+	dir := t.TempDir()
+	input := filepath.Join(dir, "api.html")
+	if err := os.WriteFile(input, []byte(fakeBotAPIPage), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewWithSource(zap.NewNop(), &telegram.FileSource{Path: input}, filepath.Join(dir, "spec-%v.json"), "botapi")
+	if err := a.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "spec-7.0.json")); err != nil {
+		t.Errorf("expected generated spec file: %v", err)
+	}
+}
+
+func TestApp_Run_ErrorKinds(t *testing.T) {
+	unreachable := newPageServer(t, "")
+	unreachable.Close()
+	a := NewWithType(zap.NewNop(), unreachable.URL, "out.json", "botapi")
+	var fetchErr *telegram.FetchError
+	if err := a.Run(context.Background()); !errors.As(err, &fetchErr) {
+		t.Errorf("expected *telegram.FetchError for unreachable docs, got %v", err)
+	}
+
+	empty := newPageServer(t, `<!DOCTYPE html><html><body><strong>Bot API 7.0</strong></body></html>`)
+	a = NewWithType(zap.NewNop(), empty.URL, "out.json", "botapi")
+	if err := a.Run(context.Background()); !errors.Is(err, telegram.ErrLayoutChanged) {
+		t.Errorf("expected ErrLayoutChanged for a page without types or methods, got %v", err)
+	}
+}
+
+func TestApp_Run_Cancelled(t *testing.T) {
+	srv := newPageServer(t, fakeBotAPIPage)
+	dir := t.TempDir()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	a := NewWithType(zap.NewNop(), srv.URL, filepath.Join(dir, "spec.json"), "botapi")
+	if err := a.Run(ctx); !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected context.Canceled, got %v", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("cancelled run must not leave files behind, found %v", entries)
+	}
+}
+
+func TestApp_ParseAndGenerateFromModel(t *testing.T) {
+	srv := newPageServer(t, fakeBotAPIPage)
+	dir := t.TempDir()
+
+	var buf bytes.Buffer
+	if err := NewWithType(zap.NewNop(), srv.URL, "", "botapi").Parse(context.Background(), &buf); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	modelPath := filepath.Join(dir, "model.json")
+	if err := os.WriteFile(modelPath, buf.Bytes(), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	a := NewFromModel(zap.NewNop(), modelPath, filepath.Join(dir, "spec-%v.json"))
+	if err := a.Run(context.Background()); err != nil {
+		t.Fatalf("Run() from model error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "spec-7.0.json"))
+	if err != nil {
+		t.Fatalf("expected spec generated from model: %v", err)
+	}
+	var spec map[string]any
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := spec["paths"].(map[string]any)["/sendMessage"]; !ok {
+		t.Error("expected /sendMessage path in spec generated from model")
+	}
+}
+
+func TestApp_Run_MissingModel(t *testing.T) {
+	a := NewFromModel(zap.NewNop(), filepath.Join(t.TempDir(), "missing.json"), "out.json")
+	if err := a.Run(context.Background()); err == nil {
+		t.Error("expected error for a missing model file")
+	}
+}
+
+func TestApp_Parse_MarkdownDescriptions(t *testing.T) {
+	srv := newPageServer(t, fakeBotAPIPage)
+
+	var buf bytes.Buffer
+	a := NewWithType(zap.NewNop(), srv.URL, "", "botapi").
+		WithOptions(Options{DescriptionFormat: telegram.DescriptionMarkdown, DocsURL: "https://core.telegram.org/bots/api"})
+	if err := a.Parse(context.Background(), &buf); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := `"Returns basic information about the bot as a [User](https://core.telegram.org/bots/api#user) object."`
+	if !bytes.Contains(buf.Bytes(), []byte(want)) {
+		t.Errorf("expected Markdown description %s in model:\n%s", want, buf.String())
+	}
+}
+
+func TestApp_Parse_UnsupportedDescriptionFormat(t *testing.T) {
+	a := NewWithType(zap.NewNop(), "http://127.0.0.1:0", "", "botapi").
+		WithOptions(Options{DescriptionFormat: "html"})
+	if err := a.Parse(context.Background(), &bytes.Buffer{}); err == nil {
+		t.Error("expected an error for an unsupported description format")
+	}
+}
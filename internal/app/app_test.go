@@ -1,9 +1,15 @@
 package app
 
 import (
+	"context"
+	"fmt"
 	"testing"
 
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+
+	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest"
+	"go.uber.org/zap/zaptest/observer"
 )
 
 func TestNewWithType(t *testing.T) {
@@ -21,10 +27,34 @@ func TestNewWithType(t *testing.T) {
 func TestApp_Run_UnsupportedType(t *testing.T) {
 	log := zaptest.NewLogger(t)
 	app := NewWithType(log, "http://example.com", "output.json", "invalidtype")
-	err := app.Run()
+	err := app.Run(context.Background())
 	if err == nil {
 		t.Error("Run() with unsupported type should return error")
 	}
 }
 
+func TestApp_LogReport(t *testing.T) {
+	core, logs := observer.New(zap.DebugLevel)
+	var report telegram.Report
+	for i := 0; i < maxReportedPhrases+2; i++ {
+		report.UnparsedConstraints = append(report.UnparsedConstraints, telegram.UnparsedPhrase{Element: fmt.Sprintf("m.p%d", i), Phrase: "1-6"})
+	}
+	NewWithType(zap.New(core), "", "", "botapi").logReport(&report)
+
+	warnings := logs.FilterLevelExact(zap.WarnLevel).All()
+	if len(warnings) != 1 {
+		t.Fatalf("expected one summary warning, got %d", len(warnings))
+	}
+	fields := warnings[0].ContextMap()
+	if fields["count"] != int64(maxReportedPhrases+2) {
+		t.Errorf("count = %v, want %d", fields["count"], maxReportedPhrases+2)
+	}
+	if phrases, _ := fields["phrases"].([]interface{}); len(phrases) != maxReportedPhrases || phrases[0] != "m.p0: 1-6" {
+		t.Errorf("phrases = %v, want the first %d as element: phrase", fields["phrases"], maxReportedPhrases)
+	}
+	if n := logs.FilterLevelExact(zap.DebugLevel).Len(); n != 2 {
+		t.Errorf("expected the remaining 2 phrases at debug level, got %d", n)
+	}
+}
+
 // Note: Integration tests for Run() with real HTTP requests are not included here to avoid network dependency.
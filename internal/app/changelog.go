@@ -0,0 +1,86 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+
+	"go.uber.org/zap"
+)
+
+// Changelog output formats.
+const (
+	ChangelogMarkdown = "markdown"
+	ChangelogJSON     = "json"
+)
+
+// Changelog loads the documentation and writes its releases to w in format,
+// newest first. When since is set, only that release and newer ones are
+// written.
+func (a *App) Changelog(ctx context.Context, w io.Writer, since, format string) error {
+	if format != ChangelogMarkdown && format != ChangelogJSON {
+		return fmt.Errorf("unsupported changelog format: %s", format)
+	}
+
+	m, err := a.load(ctx)
+	if err != nil {
+		return err
+	}
+	if len(m.Releases) == 0 {
+		return fmt.Errorf("%w: no releases found", telegram.ErrLayoutChanged)
+	}
+
+	releases := make([]telegram.Release, 0, len(m.Releases))
+	for _, r := range m.Releases {
+		if since == "" || telegram.CompareVersions(r.Version, since) >= 0 {
+			releases = append(releases, r)
+		}
+	}
+	a.log.Debug("selected releases", zap.String("since", since), zap.Int("count", len(releases)))
+
+	if format == ChangelogJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "    ")
+		if err := enc.Encode(releases); err != nil {
+			return fmt.Errorf("failed to encode changelog: %w", err)
+		}
+		return nil
+	}
+	if _, err := io.WriteString(w, changelogMarkdown(releases)); err != nil {
+		return fmt.Errorf("failed to write changelog: %w", err)
+	}
+	return nil
+}
+
+// changelogMarkdown renders releases as a Markdown document with one section
+// per release.
+func changelogMarkdown(releases []telegram.Release) string {
+	var b strings.Builder
+	for i, r := range releases {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString("## Bot API " + r.Version)
+		if r.Date != "" {
+			b.WriteString(" (" + r.Date + ")")
+		}
+		b.WriteString("\n\n")
+		for _, c := range r.Changes {
+			// Continuation lines of multi-line changes stay in the item.
+			for j, line := range strings.Split(c.Text, "\n") {
+				switch {
+				case j == 0:
+					b.WriteString("- " + line)
+				case line != "":
+					b.WriteString("  " + line)
+				}
+				b.WriteString("\n")
+			}
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,180 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+
+	"go.uber.org/zap"
+)
+
+const changelogBotAPIPage = `<!DOCTYPE html><html><body>
+	<h3>Recent changes</h3>
+	<h4>August 15, 2025</h4>
+	<p><strong>Bot API 9.2</strong></p>
+	<ul><li>Added the method <a href="#getme">getMe</a>.</li></ul>
+	<h4>July 3, 2025</h4>
+	<p><strong>Bot API 9.1</strong></p>
+	<ul><li>Added the class <a href="#user">User</a>.</li></ul>
+	<h4>December 29, 2023</h4>
+	<p><strong>Bot API 7.0</strong></p>
+	<ul><li>Initial release.</li></ul>
+	<h3>Available types</h3>
+	<h4>User</h4>
+	<p>This object represents a Telegram user or bot.</p>
+	<table>
+		<thead><tr><th>Field</th><th>Type</th><th>Description</th></tr></thead>
+		<tbody><tr><td>id</td><td>Integer</td><td>Unique identifier.</td></tr></tbody>
+	</table>
+	<h3>Available methods</h3>
+	<h4>getMe</h4>
+	<p>Returns basic information about the bot as a <a href="#user">User</a> object.</p>
+</body></html>`
+
+func TestApp_Changelog_Markdown(t *testing.T) {
+	srv := newPageServer(t, changelogBotAPIPage)
+
+	var buf bytes.Buffer
+	if err := NewWithType(zap.NewNop(), srv.URL, "", "botapi").Changelog(context.Background(), &buf, "9.1", ChangelogMarkdown); err != nil {
+		t.Fatalf("Changelog() error = %v", err)
+	}
+	want := "## Bot API 9.2 (2025-08-15)\n\n- Added the method getMe.\n\n## Bot API 9.1 (2025-07-03)\n\n- Added the class User.\n"
+	if buf.String() != want {
+		t.Errorf("Changelog() =\n%s\nwant\n%s", buf.String(), want)
+	}
+}
+
+func TestApp_Changelog_JSON(t *testing.T) {
+	srv := newPageServer(t, changelogBotAPIPage)
+
+	var buf bytes.Buffer
+	if err := NewWithType(zap.NewNop(), srv.URL, "", "botapi").Changelog(context.Background(), &buf, "", ChangelogJSON); err != nil {
+		t.Fatalf("Changelog() error = %v", err)
+	}
+	var releases []telegram.Release
+	if err := json.Unmarshal(buf.Bytes(), &releases); err != nil {
+		t.Fatalf("changelog is not JSON: %v\n%s", err, buf.String())
+	}
+	if len(releases) != 3 || releases[0].Version != "9.2" || releases[0].Changes[0].Anchors[0] != "getme" {
+		t.Errorf("unexpected releases: %+v", releases)
+	}
+}
+
+func TestApp_Changelog_SinceIncludesVersion(t *testing.T) {
+	srv := newPageServer(t, changelogBotAPIPage)
+	a := NewWithType(zap.NewNop(), srv.URL, "", "botapi")
+
+	for since, want := range map[string][]string{
+		"7.0":   {"9.2", "9.1", "7.0"},
+		"9.1":   {"9.2", "9.1"},
+		"9.1.1": {"9.2"},
+		"9.3":   {},
+	} {
+		var buf bytes.Buffer
+		if err := a.Changelog(context.Background(), &buf, since, ChangelogJSON); err != nil {
+			t.Fatalf("Changelog(since %s) error = %v", since, err)
+		}
+		var releases []telegram.Release
+		if err := json.Unmarshal(buf.Bytes(), &releases); err != nil {
+			t.Fatal(err)
+		}
+		got := make([]string, len(releases))
+		for i, r := range releases {
+			got[i] = r.Version
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("Changelog(since %s) versions = %v, want %v", since, got, want)
+		}
+	}
+}
+
+func TestApp_Changelog_Errors(t *testing.T) {
+	srv := newPageServer(t, fakeBotAPIPage)
+	a := NewWithType(zap.NewNop(), srv.URL, "", "botapi")
+
+	if err := a.Changelog(context.Background(), &bytes.Buffer{}, "", "html"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+	if err := a.Changelog(context.Background(), &bytes.Buffer{}, "", ChangelogMarkdown); err == nil {
+		t.Error("expected an error for a page without releases")
+	}
+}
+
+func TestApp_Parse_Since(t *testing.T) {
+	srv := newPageServer(t, changelogBotAPIPage)
+
+	var buf bytes.Buffer
+	if err := NewWithType(zap.NewNop(), srv.URL, "", "botapi").Parse(context.Background(), &buf); err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	var m struct {
+		Types   []telegram.Type   `json:"types"`
+		Methods []telegram.Method `json:"methods"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Types) != 1 || m.Types[0].Since != "9.1" || m.Types[0].Fields[0].Since != "9.1" {
+		t.Errorf("types = %+v, want User and its fields since 9.1", m.Types)
+	}
+	if len(m.Methods) != 1 || m.Methods[0].Since != "9.2" {
+		t.Errorf("methods = %+v, want getMe since 9.2", m.Methods)
+	}
+}
+
+func TestApp_Run_AsOfVersion(t *testing.T) {
+	srv := newPageServer(t, changelogBotAPIPage)
+	dir := t.TempDir()
+
+	a := NewWithType(zap.NewNop(), srv.URL, filepath.Join(dir, "spec-%v.json"), "botapi").
+		WithOptions(Options{AsOfVersion: "9.1"})
+	if err := a.Run(context.Background()); err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "spec-9.1.json"))
+	if err != nil {
+		t.Fatalf("expected spec named after the requested version: %v", err)
+	}
+	var spec struct {
+		Info       struct{ Version string }         `json:"info"`
+		Paths      map[string]any                   `json:"paths"`
+		Components struct{ Schemas map[string]any } `json:"components"`
+	}
+	if err := json.Unmarshal(data, &spec); err != nil {
+		t.Fatal(err)
+	}
+	if spec.Info.Version != "9.1" {
+		t.Errorf("info.version = %q, want 9.1", spec.Info.Version)
+	}
+	if _, ok := spec.Paths["/getMe"]; ok {
+		t.Error("getMe was added in 9.2 and must be dropped")
+	}
+	if _, ok := spec.Components.Schemas["User"]; !ok {
+		t.Error("User was added in 9.1 and must be kept")
+	}
+}
+
+func TestApp_Run_AsOfVersionErrors(t *testing.T) {
+	tests := []struct {
+		name, page, version string
+	}{
+		{name: "newer than documented", page: changelogBotAPIPage, version: "9.3"},
+		{name: "no releases", page: fakeBotAPIPage, version: "7.0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newPageServer(t, tt.page)
+			a := NewWithType(zap.NewNop(), srv.URL, filepath.Join(t.TempDir(), "spec.json"), "botapi").
+				WithOptions(Options{AsOfVersion: tt.version})
+			if err := a.Run(context.Background()); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
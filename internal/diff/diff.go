@@ -0,0 +1,240 @@
+// Package diff compares two versions of the API surface, read from generated
+// OpenAPI files or intermediate models, and classifies what changed.
+//
+// Both inputs are reduced to a Surface: the methods with their parameters and
+// result, and the types with their fields or union variants. Types are
+// spelled the way the documentation spells them ("Integer", "Array of
+// PhotoSize", "Integer or String") whatever the input, so reports read the
+// same for specs and models.
+package diff
+
+import (
+	"sort"
+	"strings"
+)
+
+// Surface is the part of an API that clients depend on.
+type Surface struct {
+	// Version is the API version, e.g. "9.1".
+	Version string
+	Methods map[string]Method
+	Types   map[string]Type
+}
+
+// Method is an API method: its parameters by name and its result type.
+type Method struct {
+	Parameters map[string]Member
+	Result     string
+}
+
+// Type is an object type with its fields by name, or a union type with its
+// variants.
+type Type struct {
+	Fields   map[string]Member
+	Variants []string
+}
+
+// Member is a parameter or a field.
+type Member struct {
+	Type     string
+	Required bool
+}
+
+// Change kinds.
+const (
+	Added    = "added"
+	Removed  = "removed"
+	Modified = "modified"
+)
+
+// Element kinds.
+const (
+	ElementMethod    = "method"
+	ElementParameter = "parameter"
+	ElementType      = "type"
+	ElementField     = "field"
+	ElementVariant   = "variant"
+)
+
+// Change is one difference between two surfaces.
+type Change struct {
+	Kind    string `json:"kind"`
+	Element string `json:"element"`
+	// Path names the element: "sendMessage", "sendMessage.text",
+	// "Message.story" or "ChatMember.ChatMemberOwner" for a variant.
+	Path string `json:"path"`
+	// Detail describes a modification, e.g. "type Integer → Integer or
+	// String" or "became required"; for additions it gives the type.
+	Detail   string `json:"detail,omitempty"`
+	Breaking bool   `json:"breaking"`
+}
+
+// Report is the result of Compare.
+type Report struct {
+	OldVersion string   `json:"old_version,omitempty"`
+	NewVersion string   `json:"new_version,omitempty"`
+	Changes    []Change `json:"changes"`
+}
+
+// Breaking reports whether any change breaks existing clients.
+func (r *Report) Breaking() bool {
+	for _, c := range r.Changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare returns the changes from before to after, sorted by path. Removals,
+// new required parameters, parameters that became required, fields that
+// became optional and any type change are breaking; additions are not.
+func Compare(before, after *Surface) *Report {
+	r := &Report{OldVersion: before.Version, NewVersion: after.Version, Changes: []Change{}}
+
+	for _, name := range keys(before.Methods, after.Methods) {
+		o, inOld := before.Methods[name]
+		n, inNew := after.Methods[name]
+		switch {
+		case !inNew:
+			r.add(Change{Kind: Removed, Element: ElementMethod, Path: name, Breaking: true})
+		case !inOld:
+			r.add(Change{Kind: Added, Element: ElementMethod, Path: name, Detail: "returns " + n.Result})
+		default:
+			if o.Result != n.Result {
+				r.add(Change{Kind: Modified, Element: ElementMethod, Path: name,
+					Detail: "result " + o.Result + " → " + n.Result, Breaking: true})
+			}
+			r.members(ElementParameter, name, o.Parameters, n.Parameters)
+		}
+	}
+
+	for _, name := range keys(before.Types, after.Types) {
+		o, inOld := before.Types[name]
+		n, inNew := after.Types[name]
+		switch {
+		case !inNew:
+			r.add(Change{Kind: Removed, Element: ElementType, Path: name, Breaking: true})
+		case !inOld:
+			r.add(Change{Kind: Added, Element: ElementType, Path: name})
+		default:
+			r.members(ElementField, name, o.Fields, n.Fields)
+			r.variants(name, o.Variants, n.Variants)
+		}
+	}
+
+	sort.SliceStable(r.Changes, func(i, j int) bool { return r.Changes[i].Path < r.Changes[j].Path })
+	return r
+}
+
+func (r *Report) add(c Change) {
+	r.Changes = append(r.Changes, c)
+}
+
+// members compares the parameters of a method or the fields of a type.
+// Required members are input for parameters and guaranteed output for
+// fields, so requiredness changes break clients in opposite directions.
+func (r *Report) members(element, owner string, before, after map[string]Member) {
+	for _, name := range keys(before, after) {
+		path := owner + "." + name
+		o, inOld := before[name]
+		n, inNew := after[name]
+		switch {
+		case !inNew:
+			r.add(Change{Kind: Removed, Element: element, Path: path, Breaking: true})
+		case !inOld:
+			r.add(Change{Kind: Added, Element: element, Path: path, Detail: describe(n),
+				Breaking: element == ElementParameter && n.Required})
+		default:
+			if o.Type != n.Type {
+				r.add(Change{Kind: Modified, Element: element, Path: path,
+					Detail: "type " + o.Type + " → " + n.Type, Breaking: true})
+			}
+			if o.Required != n.Required {
+				detail := "became optional"
+				if n.Required {
+					detail = "became required"
+				}
+				r.add(Change{Kind: Modified, Element: element, Path: path, Detail: detail,
+					Breaking: (element == ElementParameter) == n.Required})
+			}
+		}
+	}
+}
+
+func (r *Report) variants(union string, before, after []string) {
+	inOld, inNew := set(before), set(after)
+	for _, v := range before {
+		if !inNew[v] {
+			r.add(Change{Kind: Removed, Element: ElementVariant, Path: union + "." + v, Breaking: true})
+		}
+	}
+	for _, v := range after {
+		if !inOld[v] {
+			r.add(Change{Kind: Added, Element: ElementVariant, Path: union + "." + v})
+		}
+	}
+}
+
+func describe(m Member) string {
+	if m.Required {
+		return m.Type + ", required"
+	}
+	return m.Type + ", optional"
+}
+
+// keys returns the union of the keys of a and b, sorted.
+func keys[V any](a, b map[string]V) []string {
+	out := make([]string, 0, len(a)+len(b))
+	for k := range a {
+		out = append(out, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			out = append(out, k)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
+
+func set(values []string) map[string]bool {
+	s := make(map[string]bool, len(values))
+	for _, v := range values {
+		s[v] = true
+	}
+	return s
+}
+
+// primitives maps the primitive spellings of the documentation and of
+// JSON Schema to one name.
+var primitives = map[string]string{
+	"Int":          "Integer",
+	"Integer":      "Integer",
+	"integer":      "Integer",
+	"Float":        "Float",
+	"Float number": "Float",
+	"number":       "Float",
+	"Boolean":      "Boolean",
+	"True":         "Boolean",
+	"boolean":      "Boolean",
+	"String":       "String",
+	"string":       "String",
+	"object":       "Object",
+}
+
+func normalize(name string) string {
+	if p, ok := primitives[name]; ok {
+		return p
+	}
+	return name
+}
+
+// typeName spells a type: alternatives joined with "or", wrapped in
+// "Array of" depth times.
+func typeName(alternatives []string, depth int) string {
+	for i, a := range alternatives {
+		alternatives[i] = normalize(a)
+	}
+	return strings.Repeat("Array of ", depth) + strings.Join(alternatives, " or ")
+}
@@ -0,0 +1,93 @@
+package diff
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	before := &Surface{
+		Version: "9.0",
+		Methods: map[string]Method{
+			"sendMessage": {Result: "Message", Parameters: map[string]Member{
+				"chat_id":    {Type: "Integer", Required: true},
+				"parse_mode": {Type: "String"},
+				"entities":   {Type: "Array of MessageEntity"},
+			}},
+			"sendGame":     {Result: "Message"},
+			"getChatCount": {Result: "Integer"},
+		},
+		Types: map[string]Type{
+			"Message":    {Fields: map[string]Member{"message_id": {Type: "Integer", Required: true}, "from": {Type: "User", Required: true}}},
+			"ChatMember": {Variants: []string{"ChatMemberOwner", "ChatMemberBanned"}},
+			"Game":       {},
+		},
+	}
+	after := &Surface{
+		Version: "9.1",
+		Methods: map[string]Method{
+			"sendMessage": {Result: "Message", Parameters: map[string]Member{
+				"chat_id":                {Type: "Integer or String", Required: true},
+				"parse_mode":             {Type: "String", Required: true},
+				"entities":               {Type: "Array of MessageEntity"},
+				"business_connection_id": {Type: "String"},
+				"text":                   {Type: "String", Required: true},
+			}},
+			"getChatCount":  {Result: "Array of Integer"},
+			"sendChecklist": {Result: "Message"},
+		},
+		Types: map[string]Type{
+			"Message":    {Fields: map[string]Member{"message_id": {Type: "Integer", Required: true}, "from": {Type: "User"}, "story": {Type: "Story"}}},
+			"ChatMember": {Variants: []string{"ChatMemberOwner", "ChatMemberMember"}},
+			"Story":      {},
+		},
+	}
+
+	want := []Change{
+		{Kind: Removed, Element: ElementVariant, Path: "ChatMember.ChatMemberBanned", Breaking: true},
+		{Kind: Added, Element: ElementVariant, Path: "ChatMember.ChatMemberMember"},
+		{Kind: Removed, Element: ElementType, Path: "Game", Breaking: true},
+		{Kind: Modified, Element: ElementField, Path: "Message.from", Detail: "became optional", Breaking: true},
+		{Kind: Added, Element: ElementField, Path: "Message.story", Detail: "Story, optional"},
+		{Kind: Added, Element: ElementType, Path: "Story"},
+		{Kind: Modified, Element: ElementMethod, Path: "getChatCount", Detail: "result Integer → Array of Integer", Breaking: true},
+		{Kind: Added, Element: ElementMethod, Path: "sendChecklist", Detail: "returns Message"},
+		{Kind: Removed, Element: ElementMethod, Path: "sendGame", Breaking: true},
+		{Kind: Added, Element: ElementParameter, Path: "sendMessage.business_connection_id", Detail: "String, optional"},
+		{Kind: Modified, Element: ElementParameter, Path: "sendMessage.chat_id", Detail: "type Integer → Integer or String", Breaking: true},
+		{Kind: Modified, Element: ElementParameter, Path: "sendMessage.parse_mode", Detail: "became required", Breaking: true},
+		{Kind: Added, Element: ElementParameter, Path: "sendMessage.text", Detail: "String, required", Breaking: true},
+	}
+
+	got := Compare(before, after)
+	if !reflect.DeepEqual(got.Changes, want) {
+		t.Errorf("Compare() changes:\n%+v\nwant\n%+v", got.Changes, want)
+	}
+	if got.OldVersion != "9.0" || got.NewVersion != "9.1" {
+		t.Errorf("versions = %s → %s, want 9.0 → 9.1", got.OldVersion, got.NewVersion)
+	}
+	if !got.Breaking() {
+		t.Error("Breaking() = false, want true")
+	}
+}
+
+func TestCompare_NonBreaking(t *testing.T) {
+	before := &Surface{
+		Methods: map[string]Method{"getMe": {Result: "User", Parameters: map[string]Member{"limit": {Type: "Integer", Required: true}}}},
+		Types:   map[string]Type{"User": {Fields: map[string]Member{"id": {Type: "Integer"}}}},
+	}
+	after := &Surface{
+		Methods: map[string]Method{"getMe": {Result: "User", Parameters: map[string]Member{"limit": {Type: "Integer"}, "offset": {Type: "Integer"}}}},
+		Types:   map[string]Type{"User": {Fields: map[string]Member{"id": {Type: "Integer", Required: true}, "name": {Type: "String", Required: true}}}},
+	}
+	report := Compare(before, after)
+	if len(report.Changes) != 4 {
+		t.Errorf("expected 4 changes, got %+v", report.Changes)
+	}
+	if report.Breaking() {
+		t.Errorf("optional parameters, relaxed parameters and new or tightened fields are not breaking: %+v", report.Changes)
+	}
+	if same := Compare(before, before); len(same.Changes) != 0 || same.Breaking() {
+		t.Errorf("comparing a surface with itself = %+v, want no changes", same.Changes)
+	}
+}
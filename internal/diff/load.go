@@ -0,0 +1,314 @@
+package diff
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/superboomer/tg-spec-cli/internal/model"
+	"github.com/superboomer/tg-spec-cli/internal/openapi"
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+)
+
+// Input kinds, see Load.
+const (
+	KindOpenAPI = "openapi"
+	KindModel   = "model"
+)
+
+// ErrMixedInputs is returned by LoadPair when one file is a spec and the
+// other a model.
+var ErrMixedInputs = errors.New("can't compare an OpenAPI document with an intermediate model")
+
+// LoadPair reads the two files to compare, which must be of the same kind.
+func LoadPair(ctx context.Context, oldPath, newPath string) (before, after *Surface, err error) {
+	before, oldKind, err := LoadFile(ctx, oldPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	after, newKind, err := LoadFile(ctx, newPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	if oldKind != newKind {
+		return nil, nil, fmt.Errorf("%w: %s is an %s file, %s an %s file", ErrMixedInputs, oldPath, oldKind, newPath, newKind)
+	}
+	return before, after, nil
+}
+
+// LoadFile reads a generated OpenAPI document, an intermediate model or a
+// saved documentation page and returns its surface and kind. The kind is told
+// by the top-level "openapi" or "schema_version" key; YAML documents are
+// always OpenAPI. A page (a file starting with "<") is parsed like the parse
+// command does and compared as the model it yields, so its kind is
+// KindModel.
+func LoadFile(ctx context.Context, name string) (*Surface, string, error) {
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to read %s: %w", name, err)
+	}
+	if isHTML(data) {
+		s, err := loadPage(ctx, name)
+		if err != nil {
+			return nil, "", fmt.Errorf("failed to load %s: %w", name, err)
+		}
+		return s, KindModel, nil
+	}
+	s, kind, err := Load(data)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to load %s: %w", name, err)
+	}
+	return s, kind, nil
+}
+
+// Load parses data as an OpenAPI document (JSON or YAML) or an intermediate
+// model, see LoadFile.
+func Load(data []byte) (*Surface, string, error) {
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal(data, &probe); err != nil || probe["openapi"] != nil {
+		doc, loadErr := openapi.Load(bytes.NewReader(data))
+		if loadErr != nil {
+			return nil, "", loadErr
+		}
+		s, err := FromOpenAPI(doc)
+		if err != nil {
+			return nil, "", err
+		}
+		return s, KindOpenAPI, nil
+	}
+	if probe["schema_version"] == nil {
+		return nil, "", errors.New("neither an OpenAPI document nor an intermediate model")
+	}
+	m, err := model.Read(bytes.NewReader(data))
+	if err != nil {
+		return nil, "", err
+	}
+	return FromModel(m), KindModel, nil
+}
+
+// isHTML reports whether data looks like an HTML page rather than JSON or
+// YAML, neither of which can start with "<".
+func isHTML(data []byte) bool {
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	return bytes.HasPrefix(bytes.TrimSpace(data), []byte("<"))
+}
+
+// loadPage parses the documentation page saved at name into a model and
+// returns its surface.
+func loadPage(ctx context.Context, name string) (*Surface, error) {
+	page, err := telegram.LoadPage(ctx, &telegram.FileSource{Path: name})
+	if err != nil {
+		return nil, err
+	}
+	m, err := model.FromPage(ctx, "", page)
+	if err != nil {
+		return nil, err
+	}
+	return FromModel(m), nil
+}
+
+// FromModel returns the surface of an intermediate model.
+func FromModel(m *model.Model) *Surface {
+	s := &Surface{Version: m.APIVersion, Methods: map[string]Method{}, Types: map[string]Type{}}
+	for _, method := range m.Methods {
+		out := Method{Parameters: map[string]Member{}, Result: returnTypeNames(method.ReturnTypes)}
+		for _, p := range method.Parameters {
+			out.Parameters[p.Name] = Member{
+				Type:     typeName(append([]string(nil), p.Type.Types...), p.Type.ArrayDepth),
+				Required: p.Required,
+			}
+		}
+		s.Methods[method.Name] = out
+	}
+	for _, t := range m.Types {
+		out := Type{Fields: map[string]Member{}}
+		for _, f := range t.Fields {
+			alternatives := make([]string, len(f.Type))
+			for i, a := range f.Type {
+				depth := strings.Count(a, "Array of ")
+				alternatives[i] = typeName([]string{strings.ReplaceAll(a, "Array of ", "")}, depth)
+			}
+			out.Fields[f.Name] = Member{Type: strings.Join(alternatives, " or "), Required: f.Required}
+		}
+		if len(t.Fields) == 0 && telegram.IsUnionDescription(t.Description) {
+			for _, line := range strings.Split(t.Description, "\n") {
+				if v, ok := strings.CutPrefix(strings.TrimSpace(line), "-"); ok && strings.TrimSpace(v) != "" {
+					out.Variants = append(out.Variants, strings.TrimSpace(v))
+				}
+			}
+		}
+		s.Types[t.Name] = out
+	}
+	return s
+}
+
+// returnTypeNames spells the results of a method, joined with "or".
+func returnTypeNames(rts []telegram.ReturnType) string {
+	names := make([]string, len(rts))
+	for i, rt := range rts {
+		depth := rt.ArrayDepth
+		if rt.IsArray && depth == 0 {
+			depth = 1
+		}
+		names[i] = typeName([]string{rt.Name}, depth)
+	}
+	return strings.Join(names, " or ")
+}
+
+// FromOpenAPI returns the surface of an OpenAPI document. Each path is a
+// method; its parameters come from the JSON request body, or the first body
+// or the query parameters when there is none, and its result from the
+// "result" property of the 200 response. References to shared components
+// are followed; one that can't be resolved is an error.
+func FromOpenAPI(doc *openapi.OpenAPI) (*Surface, error) {
+	s := &Surface{Version: doc.Info.Version, Methods: map[string]Method{}, Types: map[string]Type{}}
+	for p, item := range doc.Paths {
+		item, err := doc.ResolvePath(item)
+		if err != nil {
+			return nil, fmt.Errorf("path %s: %w", p, err)
+		}
+		op := firstOperation(item)
+		if op == nil {
+			continue
+		}
+		method, err := operationSurface(doc, op)
+		if err != nil {
+			return nil, fmt.Errorf("path %s: %w", p, err)
+		}
+		s.Methods[strings.TrimPrefix(p, "/")] = method
+	}
+	for name, schema := range doc.Components.Schemas {
+		t := Type{Fields: map[string]Member{}}
+		for field, prop := range schema.Properties {
+			t.Fields[field] = Member{Type: schemaType(prop), Required: contains(schema.Required, field)}
+		}
+		for _, v := range schema.OneOf {
+			t.Variants = append(t.Variants, schemaType(v))
+		}
+		s.Types[name] = t
+	}
+	return s, nil
+}
+
+// operationSurface returns the parameters and result of op.
+func operationSurface(doc *openapi.OpenAPI, op *openapi.Operation) (Method, error) {
+	method := Method{Parameters: map[string]Member{}}
+	body, err := requestSchema(doc, op.RequestBody)
+	if err != nil {
+		return Method{}, err
+	}
+	if body != nil {
+		for name, prop := range body.Properties {
+			method.Parameters[name] = Member{Type: schemaType(prop), Required: contains(body.Required, name)}
+		}
+	} else {
+		for _, param := range op.Parameters {
+			param, err := doc.ResolveParameter(param)
+			if err != nil {
+				return Method{}, err
+			}
+			m := Member{Required: param.Required}
+			if param.Schema != nil {
+				m.Type = schemaType(*param.Schema)
+			} else if mt, ok := param.Content["application/json"]; ok && mt.Schema != nil {
+				m.Type = schemaType(*mt.Schema)
+			}
+			method.Parameters[param.Name] = m
+		}
+	}
+
+	res, ok := op.Responses["200"]
+	if !ok {
+		return method, nil
+	}
+	if res, err = doc.ResolveResponse(res); err != nil {
+		return Method{}, err
+	}
+	envelope, err := resolvedSchema(doc, res.Content["application/json"].Schema)
+	if err != nil || envelope == nil {
+		return method, err
+	}
+	if result, ok := envelope.Properties["result"]; ok {
+		method.Result = schemaType(result)
+	}
+	return method, nil
+}
+
+func firstOperation(p openapi.Path) *openapi.Operation {
+	for _, op := range []*openapi.Operation{p.Post, p.Get, p.Put, p.Patch, p.Delete, p.Head, p.Options, p.Trace} {
+		if op != nil {
+			return op
+		}
+	}
+	return nil
+}
+
+// requestSchema returns the schema of the JSON body of rb, or of its only
+// other body, following references.
+func requestSchema(doc *openapi.OpenAPI, rb *openapi.RequestBody) (*openapi.Schema, error) {
+	if rb == nil {
+		return nil, nil
+	}
+	body, err := doc.ResolveRequestBody(*rb)
+	if err != nil {
+		return nil, err
+	}
+	for _, ct := range []string{"application/json", "multipart/form-data", "application/x-www-form-urlencoded"} {
+		if mt, ok := body.Content[ct]; ok {
+			return resolvedSchema(doc, mt.Schema)
+		}
+	}
+	return nil, nil
+}
+
+// resolvedSchema returns s, or the shared schema it refers to. A nil s
+// stays nil.
+func resolvedSchema(doc *openapi.OpenAPI, s *openapi.Schema) (*openapi.Schema, error) {
+	if s == nil || s.Ref == "" {
+		return s, nil
+	}
+	resolved, err := doc.ResolveSchema(s.Ref)
+	if err != nil {
+		return nil, err
+	}
+	return &resolved, nil
+}
+
+// schemaType spells the type a schema describes. Nullable types read
+// "String or null".
+func schemaType(s openapi.Schema) string {
+	alternatives := s.OneOf
+	if len(alternatives) == 0 {
+		alternatives = s.AnyOf
+	}
+	switch {
+	case s.Ref != "":
+		return path.Base(s.Ref)
+	case len(alternatives) > 0:
+		names := make([]string, len(alternatives))
+		for i, alt := range alternatives {
+			names[i] = schemaType(alt)
+		}
+		return strings.Join(names, " or ")
+	case s.Type.Is("array") && s.Items != nil:
+		return "Array of " + schemaType(*s.Items)
+	case s.Type.Is("string") && s.Format == "binary":
+		return "InputFile"
+	default:
+		return typeName(append([]string(nil), s.Type...), 0)
+	}
+}
+
+func contains(values []string, v string) bool {
+	for _, x := range values {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
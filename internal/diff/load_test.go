@@ -0,0 +1,210 @@
+package diff
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/superboomer/tg-spec-cli/internal/generator"
+	"github.com/superboomer/tg-spec-cli/internal/model"
+	"github.com/superboomer/tg-spec-cli/internal/openapi"
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+
+	"go.uber.org/zap"
+)
+
+func sampleModel() *model.Model {
+	types := map[string]telegram.Type{
+		"user": {Name: "User", Fields: []telegram.Field{
+			{Name: "id", Type: []string{"Integer"}, Required: true},
+			{Name: "is_premium", Type: []string{"True"}},
+		}},
+		"message": {Name: "Message", Fields: []telegram.Field{
+			{Name: "from", Type: []string{"User"}},
+			{Name: "photo", Type: []string{"Array of PhotoSize"}},
+		}},
+		"photosize":        {Name: "PhotoSize", Fields: []telegram.Field{{Name: "width", Type: []string{"Integer"}, Required: true}}},
+		"inputfile":        {Name: "InputFile"},
+		"chatmember":       {Name: "ChatMember", Description: "It can be one of\n- ChatMemberOwner\n- ChatMemberMember\n"},
+		"chatmemberowner":  {Name: "ChatMemberOwner", Fields: []telegram.Field{{Name: "status", Type: []string{"String"}, Required: true, Const: "creator"}}},
+		"chatmembermember": {Name: "ChatMemberMember", Fields: []telegram.Field{{Name: "status", Type: []string{"String"}, Required: true, Const: "member"}}},
+	}
+	methods := []telegram.Method{
+		{Name: "sendPhoto", ReturnTypes: []telegram.ReturnType{{Name: "Message"}}, Parameters: []telegram.Parameter{
+			{Name: "chat_id", Type: telegram.DataType{Types: []string{"Integer", "String"}}, Required: true},
+			{Name: "photo", Type: telegram.DataType{Types: []string{"InputFile", "String"}}, Required: true},
+			{Name: "caption_entities", Type: telegram.DataType{Types: []string{"MessageEntity"}, IsArray: true, ArrayDepth: 1}},
+		}},
+		{Name: "getUpdates", ReturnTypes: []telegram.ReturnType{{Name: "Update", IsArray: true, ArrayDepth: 1}}},
+		{Name: "editMessageText", ReturnTypes: []telegram.ReturnType{{Name: "Message"}, {Name: "boolean"}}},
+	}
+	return model.New("botapi", "9.1", types, methods)
+}
+
+// TestLoad_SpecMatchesModel checks that a model and the spec generated from
+// it have the same surface, so either can be diffed.
+func TestLoad_SpecMatchesModel(t *testing.T) {
+	m := sampleModel()
+	var modelJSON bytes.Buffer
+	if err := m.Write(&modelJSON); err != nil {
+		t.Fatal(err)
+	}
+	spec, err := generator.NewWithType(zap.NewNop(), m.APIVersion, m.TypeMap(), m.Methods, m.APIType).Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	specJSON, err := json.Marshal(spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fromModel, kind, err := Load(modelJSON.Bytes())
+	if err != nil || kind != KindModel {
+		t.Fatalf("Load(model) kind = %q, err = %v", kind, err)
+	}
+	fromSpec, kind, err := Load(specJSON)
+	if err != nil || kind != KindOpenAPI {
+		t.Fatalf("Load(spec) kind = %q, err = %v", kind, err)
+	}
+
+	// The spec adds the error envelope the model doesn't have.
+	delete(fromSpec.Types, "ErrorResponse")
+	if report := Compare(fromModel, fromSpec); len(report.Changes) != 0 {
+		t.Errorf("model and spec surfaces differ: %+v", report.Changes)
+	}
+
+	want := Method{Result: "Message", Parameters: map[string]Member{
+		"chat_id":          {Type: "Integer or String", Required: true},
+		"photo":            {Type: "InputFile or String", Required: true},
+		"caption_entities": {Type: "Array of MessageEntity"},
+	}}
+	if got := fromSpec.Methods["sendPhoto"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("sendPhoto = %+v, want %+v", got, want)
+	}
+	if got := fromModel.Methods["editMessageText"].Result; got != "Message or Boolean" {
+		t.Errorf("editMessageText result = %q, want Message or Boolean", got)
+	}
+}
+
+func TestLoad_OpenAPIReferences(t *testing.T) {
+	spec := `{"openapi": "3.1.0", "info": {"version": "9.1"},
+		"paths": {
+			"/getMe": {"post": {"responses": {"200": {"$ref": "#/components/responses/GetMe"}}}},
+			"/close": {"post": {"responses": {"200": {"description": "No schema", "content": {"application/json": {}}}}}},
+			"/sendDice": {"post": {
+				"requestBody": {"$ref": "#/components/requestBodies/SendDice"},
+				"responses": {"200": {"description": "OK", "content": {"application/json": {"schema": {"$ref": "#/components/schemas/MessageResponse"}}}}}}},
+			"/getChat": {"get": {
+				"parameters": [{"$ref": "#/components/parameters/ChatID"}],
+				"responses": {"200": {"description": "No content"}}}}
+		},
+		"components": {
+			"schemas": {
+				"User": {"type": "object"},
+				"MessageResponse": {"type": "object", "properties": {"result": {"$ref": "#/components/schemas/Message"}}}
+			},
+			"responses": {"GetMe": {"description": "OK", "content": {"application/json": {"schema": {"type": "object", "properties": {"result": {"$ref": "#/components/schemas/User"}}}}}}},
+			"requestBodies": {"SendDice": {"content": {"application/json": {"schema": {"type": "object", "required": ["chat_id"], "properties": {"chat_id": {"type": "integer"}}}}}}},
+			"parameters": {"ChatID": {"name": "chat_id", "in": "query", "required": true, "schema": {"type": "integer"}}}
+		}}`
+	s, _, err := Load([]byte(spec))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]Method{
+		"getMe":    {Result: "User", Parameters: map[string]Member{}},
+		"close":    {Parameters: map[string]Member{}},
+		"sendDice": {Result: "Message", Parameters: map[string]Member{"chat_id": {Type: "Integer", Required: true}}},
+		"getChat":  {Parameters: map[string]Member{"chat_id": {Type: "Integer", Required: true}}},
+	}
+	if !reflect.DeepEqual(s.Methods, want) {
+		t.Errorf("methods = %+v, want %+v", s.Methods, want)
+	}
+
+	dangling := `{"openapi": "3.1.0", "info": {"version": "9.1"}, "paths": {"/getMe": {"post": {"responses": {"200": {"$ref": "#/components/responses/Missing"}}}}}}`
+	if _, _, err := Load([]byte(dangling)); !errors.Is(err, openapi.ErrUnresolvedRef) {
+		t.Errorf("Load(dangling ref) error = %v, want ErrUnresolvedRef", err)
+	}
+}
+
+func TestLoadPair(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+	spec := write("spec.json", `{"openapi": "3.1.0", "info": {"version": "9.1"}, "paths": {}}`)
+	m := write("model.json", `{"schema_version": 2, "api_version": "9.1", "types": [], "methods": []}`)
+	yamlSpec := write("spec.yaml", "openapi: 3.1.0\ninfo:\n  version: \"9.2\"\npaths: {}\n")
+	other := write("other.json", `{"name": "x"}`)
+	page := write("api.html", `<!DOCTYPE html><html><body>
+		<p><strong>Bot API 9.1</strong></p>
+		<h4>getMe</h4>
+		<p>A simple method for testing your bot's authentication token. Returns basic information about the bot in form of a User object.</p>
+	</body></html>`)
+	empty := write("empty.html", `<html><body></body></html>`)
+	ctx := context.Background()
+
+	if _, _, err := LoadPair(ctx, spec, spec); err != nil {
+		t.Errorf("LoadPair(spec, spec) error = %v", err)
+	}
+	if _, _, err := LoadPair(ctx, spec, yamlSpec); err != nil {
+		t.Errorf("LoadPair(json spec, yaml spec) error = %v", err)
+	}
+	if _, _, err := LoadPair(ctx, m, m); err != nil {
+		t.Errorf("LoadPair(model, model) error = %v", err)
+	}
+	if _, _, err := LoadPair(ctx, spec, m); !errors.Is(err, ErrMixedInputs) {
+		t.Errorf("LoadPair(spec, model) error = %v, want ErrMixedInputs", err)
+	}
+	before, after, err := LoadPair(ctx, page, m)
+	if err != nil {
+		t.Fatalf("LoadPair(page, model) error = %v", err)
+	}
+	if _, ok := before.Methods["getMe"]; !ok || before.Version != "9.1" || len(after.Methods) != 0 {
+		t.Errorf("LoadPair(page, model) = %+v, %+v", before, after)
+	}
+	if _, _, err := LoadPair(ctx, page, spec); !errors.Is(err, ErrMixedInputs) {
+		t.Errorf("LoadPair(page, spec) error = %v, want ErrMixedInputs", err)
+	}
+	if _, _, err := LoadPair(ctx, page, empty); !errors.Is(err, telegram.ErrLayoutChanged) {
+		t.Errorf("LoadPair(page, empty page) error = %v, want ErrLayoutChanged", err)
+	}
+	if _, _, err := LoadPair(ctx, spec, other); err == nil {
+		t.Error("expected an error for an unknown document")
+	}
+	if _, _, err := LoadPair(ctx, spec, filepath.Join(dir, "missing.json")); err == nil {
+		t.Error("expected an error for a missing file")
+	}
+}
+
+func TestSchemaType(t *testing.T) {
+	tests := []struct {
+		schema string
+		want   string
+	}{
+		{`{"$ref": "#/components/schemas/Message"}`, "Message"},
+		{`{"type": "integer"}`, "Integer"},
+		{`{"type": ["string", "null"]}`, "String or null"},
+		{`{"type": "array", "items": {"type": "array", "items": {"$ref": "#/components/schemas/PhotoSize"}}}`, "Array of Array of PhotoSize"},
+		{`{"type": "string", "format": "binary"}`, "InputFile"},
+		{`{"anyOf": [{"type": "integer"}, {"type": "string"}]}`, "Integer or String"},
+	}
+	for _, tt := range tests {
+		var s openapi.Schema
+		if err := json.Unmarshal([]byte(tt.schema), &s); err != nil {
+			t.Fatal(err)
+		}
+		if got := schemaType(s); got != tt.want {
+			t.Errorf("schemaType(%s) = %q, want %q", tt.schema, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Report formats.
+const (
+	FormatText     = "text"
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+)
+
+// Write writes the report to w in format.
+func (r *Report) Write(w io.Writer, format string) error {
+	var out string
+	switch format {
+	case FormatText:
+		out = r.text()
+	case FormatMarkdown:
+		out = r.markdown()
+	case FormatJSON:
+		data, err := json.MarshalIndent(r, "", "    ")
+		if err != nil {
+			return fmt.Errorf("failed to encode report: %w", err)
+		}
+		out = string(data) + "\n"
+	default:
+		return fmt.Errorf("unsupported report format: %s", format)
+	}
+	if _, err := io.WriteString(w, out); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return nil
+}
+
+// text lists one change per line, breaking ones marked with "!".
+func (r *Report) text() string {
+	var b strings.Builder
+	b.WriteString(r.title() + "\n")
+	if len(r.Changes) == 0 {
+		b.WriteString("No changes.\n")
+		return b.String()
+	}
+	for _, c := range r.Changes {
+		mark := " "
+		if c.Breaking {
+			mark = "!"
+		}
+		line := fmt.Sprintf("%s %-8s %-9s %s", mark, c.Kind, c.Element, c.Path)
+		if c.Detail != "" {
+			line += " (" + c.Detail + ")"
+		}
+		b.WriteString(line + "\n")
+	}
+	breaking := 0
+	for _, c := range r.Changes {
+		if c.Breaking {
+			breaking++
+		}
+	}
+	fmt.Fprintf(&b, "%d changes, %d breaking.\n", len(r.Changes), breaking)
+	return b.String()
+}
+
+// markdown lists the breaking changes first, then the others.
+func (r *Report) markdown() string {
+	var breaking, other []string
+	for _, c := range r.Changes {
+		item := fmt.Sprintf("- %s %s `%s`", capitalize(c.Kind), c.Element, c.Path)
+		if c.Detail != "" {
+			item += ": " + c.Detail
+		}
+		if c.Breaking {
+			breaking = append(breaking, item)
+		} else {
+			other = append(other, item)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString("# " + r.title() + "\n")
+	if len(r.Changes) == 0 {
+		b.WriteString("\nNo changes.\n")
+	}
+	if len(breaking) > 0 {
+		b.WriteString("\n## Breaking changes\n\n" + strings.Join(breaking, "\n") + "\n")
+	}
+	if len(other) > 0 {
+		b.WriteString("\n## Other changes\n\n" + strings.Join(other, "\n") + "\n")
+	}
+	return b.String()
+}
+
+func (r *Report) title() string {
+	if r.OldVersion == "" && r.NewVersion == "" {
+		return "API changes"
+	}
+	return fmt.Sprintf("API changes from %s to %s", r.OldVersion, r.NewVersion)
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
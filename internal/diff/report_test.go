@@ -0,0 +1,74 @@
+package diff
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+)
+
+func sampleReport() *Report {
+	return &Report{OldVersion: "9.0", NewVersion: "9.1", Changes: []Change{
+		{Kind: Removed, Element: ElementMethod, Path: "sendGame", Breaking: true},
+		{Kind: Added, Element: ElementParameter, Path: "sendMessage.text", Detail: "String, optional"},
+	}}
+}
+
+func TestReport_Write(t *testing.T) {
+	tests := []struct {
+		format string
+		want   string
+	}{
+		{
+			format: FormatText,
+			want: "API changes from 9.0 to 9.1\n" +
+				"! removed  method    sendGame\n" +
+				"  added    parameter sendMessage.text (String, optional)\n" +
+				"2 changes, 1 breaking.\n",
+		},
+		{
+			format: FormatMarkdown,
+			want: "# API changes from 9.0 to 9.1\n\n" +
+				"## Breaking changes\n\n- Removed method `sendGame`\n\n" +
+				"## Other changes\n\n- Added parameter `sendMessage.text`: String, optional\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := sampleReport().Write(&buf, tt.format); err != nil {
+				t.Fatal(err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("Write(%s) =\n%s\nwant\n%s", tt.format, buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestReport_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := sampleReport().Write(&buf, FormatJSON); err != nil {
+		t.Fatal(err)
+	}
+	var got Report
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("report is not JSON: %v", err)
+	}
+	if len(got.Changes) != 2 || !got.Changes[0].Breaking || got.NewVersion != "9.1" {
+		t.Errorf("unexpected report: %+v", got)
+	}
+}
+
+func TestReport_WriteEmptyAndUnknownFormat(t *testing.T) {
+	var buf bytes.Buffer
+	empty := &Report{}
+	if err := empty.Write(&buf, FormatText); err != nil {
+		t.Fatal(err)
+	}
+	if buf.String() != "API changes\nNo changes.\n" {
+		t.Errorf("empty report = %q", buf.String())
+	}
+	if err := empty.Write(&buf, "html"); err == nil {
+		t.Error("expected an error for an unsupported format")
+	}
+}
@@ -0,0 +1,296 @@
+package generator
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/superboomer/tg-spec-cli/internal/openapi"
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+
+	"go.uber.org/zap"
+)
+
+const (
+	contentTypeJSON      = "application/json"
+	contentTypeForm      = "application/x-www-form-urlencoded"
+	contentTypeMultipart = "multipart/form-data"
+)
+
+// Options select the request styles emitted for each method.
+type Options struct {
+	// HTTPMethods are the lowercase HTTP methods every API method is
+	// exposed on. Empty means POST only.
+	HTTPMethods []string
+	// ContentTypes are the request body content types of non-GET
+	// operations. Empty means application/json, plus multipart/form-data
+	// for methods that upload files.
+	ContentTypes []string
+}
+
+var contentTypeAliases = map[string]string{
+	"json":       contentTypeJSON,
+	"form":       contentTypeForm,
+	"urlencoded": contentTypeForm,
+	"multipart":  contentTypeMultipart,
+}
+
+// ParseOptions validates and normalizes HTTP method and content type names as
+// given on the command line. Content types may be full MIME types or the
+// aliases json, form (or urlencoded) and multipart.
+func ParseOptions(methods, contentTypes []string) (Options, error) {
+	var opts Options
+	seen := make(map[string]bool)
+	for _, m := range methods {
+		m = strings.ToLower(strings.TrimSpace(m))
+		if m != "get" && m != "post" {
+			return Options{}, fmt.Errorf("unsupported HTTP method %q: the API accepts only get and post", m)
+		}
+		if !seen[m] {
+			seen[m] = true
+			opts.HTTPMethods = append(opts.HTTPMethods, m)
+		}
+	}
+	for _, ct := range contentTypes {
+		ct = strings.ToLower(strings.TrimSpace(ct))
+		if alias, ok := contentTypeAliases[ct]; ok {
+			ct = alias
+		}
+		switch ct {
+		case contentTypeJSON, contentTypeForm, contentTypeMultipart:
+		default:
+			return Options{}, fmt.Errorf("unsupported content type %q: use json, form or multipart", ct)
+		}
+		if !seen[ct] {
+			seen[ct] = true
+			opts.ContentTypes = append(opts.ContentTypes, ct)
+		}
+	}
+	return opts, nil
+}
+
+// operations returns the operations of method m, one per configured HTTP
+// method. The first one gets m's name as its operation ID; the others are
+// suffixed with their HTTP method (e.g. sendMessageGet). Methods with a
+// required parameter that can only be a file get no GET operation, since a
+// query string can't carry it.
+func (g *Generator) operations(m telegram.Method, properties map[string]openapi.Schema, required []string, unions map[string][]string) openapi.Path {
+	methods := g.opts.HTTPMethods
+	if len(methods) == 0 {
+		methods = []string{"post"}
+	}
+
+	var path openapi.Path
+	first := true
+	for _, verb := range methods {
+		if verb == "get" {
+			if files := fileOnlyParameters(m, properties, true); len(files) > 0 {
+				g.log.Warn("skipping GET operation: required parameters can only be uploaded as files",
+					zap.String("method", m.Name), zap.Strings("parameters", files))
+				continue
+			}
+			if files := fileOnlyParameters(m, properties, false); len(files) > 0 {
+				g.log.Debug("leaving file-only parameters out of the GET operation",
+					zap.String("method", m.Name), zap.Strings("parameters", files))
+			}
+		}
+		op := &openapi.Operation{
+			Summary:              m.Name,
+			Description:          m.Description,
+			OperationID:          m.Name,
+			Responses:            g.responses(m),
+			Deprecated:           m.Deprecated,
+			XTelegramReplacement: m.Replacement,
+			ExternalDocs:         g.externalDocs(m.Anchor),
+			XTelegramAnchor:      m.Anchor,
+			XTelegramSince:       m.Since,
+		}
+		if m.Section != "" {
+			op.Tags = []string{m.Section}
+		}
+		if !first {
+			op.OperationID += strings.ToUpper(verb[:1]) + verb[1:]
+		}
+		first = false
+		switch verb {
+		case "get":
+			op.Parameters = queryParameters(m, properties)
+			path.Get = op
+		default:
+			op.RequestBody = g.requestBody(m, properties, required, unions)
+			path.Post = op
+		}
+	}
+	return path
+}
+
+// responses returns the responses of method m: the result wrapped in the
+// {ok, result} envelope and, for the Bot API, the shared error responses.
+func (g *Generator) responses(m telegram.Method) map[string]openapi.Response {
+	responses := map[string]openapi.Response{}
+	if g.isBotAPI() {
+		responses = errorResponseRefs()
+	}
+	responses["200"] = openapi.Response{
+		Description: "Successful response",
+		Content: map[string]openapi.MediaType{
+			contentTypeJSON: {
+				Schema: &openapi.Schema{
+					Type: openapi.Types{"object"},
+					Properties: map[string]openapi.Schema{
+						"ok": {
+							Type:        openapi.Types{"boolean"},
+							Description: "Request success indicator",
+						},
+						"result": g.convertMethodReturnTypes(m.ReturnTypes),
+					},
+					Required: []string{"ok", "result"},
+				},
+			},
+		},
+	}
+	return responses
+}
+
+// requestBody returns the body of a non-GET operation in every configured
+// content type.
+func (g *Generator) requestBody(m telegram.Method, properties map[string]openapi.Schema, required []string, unions map[string][]string) *openapi.RequestBody {
+	direct, nested := g.uploadsFiles(m, unions)
+	contentTypes := g.opts.ContentTypes
+	if len(contentTypes) == 0 {
+		contentTypes = []string{contentTypeJSON}
+		if direct || nested {
+			contentTypes = append(contentTypes, contentTypeMultipart)
+		}
+	}
+
+	body := &openapi.RequestBody{
+		Content:  make(map[string]openapi.MediaType, len(contentTypes)),
+		Required: true,
+	}
+	for _, ct := range contentTypes {
+		switch ct {
+		case contentTypeJSON:
+			schema := &openapi.Schema{Type: openapi.Types{"object"}, Required: required}
+			if len(properties) > 0 {
+				schema.Properties = properties
+			}
+			body.Content[ct] = openapi.MediaType{Schema: schema}
+		case contentTypeForm:
+			body.Content[ct] = formBody(properties, required, withoutInputFile)
+		case contentTypeMultipart:
+			body.Content[ct] = multipartBody(properties, required, nested)
+		}
+	}
+	return body
+}
+
+// formBody returns a form body (urlencoded or multipart) built from the JSON
+// body properties. convert adapts each property to the form, dropping it
+// when it returns false; object and array parameters are marked as
+// JSON-serialized parts.
+func formBody(properties map[string]openapi.Schema, required []string, convert func(openapi.Schema) (openapi.Schema, bool)) openapi.MediaType {
+	media := openapi.MediaType{
+		Schema: &openapi.Schema{Type: openapi.Types{"object"}},
+	}
+	for name, property := range properties {
+		part, ok := convert(property)
+		if !ok {
+			continue
+		}
+		if media.Schema.Properties == nil {
+			media.Schema.Properties = make(map[string]openapi.Schema, len(properties))
+		}
+		media.Schema.Properties[name] = part
+		if isJSONPart(part) {
+			if media.Encoding == nil {
+				media.Encoding = make(map[string]openapi.Encoding)
+			}
+			media.Encoding[name] = openapi.Encoding{ContentType: contentTypeJSON}
+		}
+	}
+	for _, name := range required {
+		if _, ok := media.Schema.Properties[name]; ok {
+			media.Schema.Required = append(media.Schema.Required, name)
+		}
+	}
+	return media
+}
+
+// queryParameters returns the parameters of a GET operation. Files can't be
+// sent in a query string, so InputFile alternatives are dropped; objects and
+// arrays are JSON-serialized.
+func queryParameters(m telegram.Method, properties map[string]openapi.Schema) []openapi.Parameter {
+	var params []openapi.Parameter
+	for _, param := range m.Parameters {
+		property, ok := withoutInputFile(properties[param.Name])
+		if !ok {
+			continue
+		}
+		p := openapi.Parameter{
+			Name:                 param.Name,
+			In:                   "query",
+			Description:          param.Description,
+			Required:             param.Required,
+			Deprecated:           param.Deprecated,
+			XTelegramReplacement: param.Replacement,
+			XTelegramSince:       param.Since,
+		}
+		if isJSONPart(property) {
+			p.Content = map[string]openapi.MediaType{
+				contentTypeJSON: {Schema: &property},
+			}
+		} else {
+			p.Schema = &property
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+// fileOnlyParameters returns the parameters of m, required or optional ones,
+// that can only be sent as a file and so are left out of query strings.
+func fileOnlyParameters(m telegram.Method, properties map[string]openapi.Schema, required bool) []string {
+	var names []string
+	for _, param := range m.Parameters {
+		if param.Required != required {
+			continue
+		}
+		if _, ok := withoutInputFile(properties[param.Name]); !ok {
+			names = append(names, param.Name)
+		}
+	}
+	return names
+}
+
+// withoutInputFile returns a copy of property without its InputFile
+// alternatives, or false if nothing but a file is left ("InputFile" alone,
+// "Array of InputFile").
+func withoutInputFile(property openapi.Schema) (openapi.Schema, bool) {
+	if property.Ref == inputFileRef {
+		return openapi.Schema{}, false
+	}
+	if property.Items != nil {
+		items, ok := withoutInputFile(*property.Items)
+		if !ok {
+			return openapi.Schema{}, false
+		}
+		property.Items = &items
+	}
+	if property.OneOf != nil {
+		oneOf := make([]openapi.Schema, 0, len(property.OneOf))
+		for _, p := range property.OneOf {
+			if p, ok := withoutInputFile(p); ok {
+				oneOf = append(oneOf, p)
+			}
+		}
+		switch len(oneOf) {
+		case 0:
+			return openapi.Schema{}, false
+		case 1:
+			oneOf[0].Description = property.Description
+			return oneOf[0], true
+		}
+		property.OneOf = oneOf
+	}
+	return property, true
+}
@@ -0,0 +1,171 @@
+package generator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/superboomer/tg-spec-cli/internal/openapi"
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestParseOptions(t *testing.T) {
+	opts, err := ParseOptions([]string{"GET", "post", "get"}, []string{"json", "form", "multipart/form-data", "application/json"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Options{
+		HTTPMethods:  []string{"get", "post"},
+		ContentTypes: []string{contentTypeJSON, contentTypeForm, contentTypeMultipart},
+	}
+	if !reflect.DeepEqual(opts, want) {
+		t.Errorf("ParseOptions() = %+v, want %+v", opts, want)
+	}
+
+	if _, err := ParseOptions([]string{"put"}, nil); err == nil {
+		t.Error("expected an error for an unsupported HTTP method")
+	}
+	if _, err := ParseOptions(nil, []string{"text/plain"}); err == nil {
+		t.Error("expected an error for an unsupported content type")
+	}
+}
+
+func TestGenerate_GetAndFormOperations(t *testing.T) {
+	opts, err := ParseOptions([]string{"get", "post"}, []string{"form", "multipart"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	spec, err := NewWithType(zap.NewNop(), "7.0", uploadTypes(), uploadMethods(), "botapi").WithOptions(opts).Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	path := spec.Paths["/sendDocument"]
+	if path.Get == nil || path.Post == nil {
+		t.Fatalf("expected GET and POST operations, got %+v", path)
+	}
+	if path.Get.OperationID != "sendDocument" || path.Post.OperationID != "sendDocumentPost" {
+		t.Errorf("operation IDs = %q, %q", path.Get.OperationID, path.Post.OperationID)
+	}
+	if path.Get.RequestBody != nil {
+		t.Error("GET must not have a request body")
+	}
+
+	query := map[string]openapi.Parameter{}
+	for _, p := range path.Get.Parameters {
+		if p.In != "query" {
+			t.Errorf("parameter %s is in %q, want query", p.Name, p.In)
+		}
+		query[p.Name] = p
+	}
+	if doc := query["document"].Schema; doc == nil || !doc.Type.Is("string") {
+		t.Errorf("document query parameter = %+v, want the String alternative only", query["document"])
+	}
+	if _, ok := query["reply_markup"].Content[contentTypeJSON]; !ok {
+		t.Errorf("reply_markup should be a JSON-serialized query parameter, got %+v", query["reply_markup"])
+	}
+
+	content := path.Post.RequestBody.Content
+	if _, ok := content[contentTypeJSON]; ok || len(content) != 2 {
+		t.Errorf("expected only the requested content types, got %v", content)
+	}
+	form := content[contentTypeForm]
+	if form.Encoding["reply_markup"].ContentType != contentTypeJSON {
+		t.Errorf("form encoding = %+v, want reply_markup as JSON", form.Encoding)
+	}
+	if _, ok := spec.Paths["/sendMessage"].Post.RequestBody.Content[contentTypeMultipart]; !ok {
+		t.Error("an explicit content type list applies to every method")
+	}
+}
+
+func TestGenerate_GetSkipsRequiredFileParameters(t *testing.T) {
+	methods := []telegram.Method{
+		{Name: "setWebhook", ReturnTypes: []telegram.ReturnType{{Name: "boolean"}}, Parameters: []telegram.Parameter{
+			{Name: "url", Type: telegram.DataType{Types: []string{"String"}}, Required: true},
+			{Name: "certificate", Type: telegram.DataType{Types: []string{"InputFile"}}},
+		}},
+		{Name: "uploadStickerFile", ReturnTypes: []telegram.ReturnType{{Name: "File"}}, Parameters: []telegram.Parameter{
+			{Name: "user_id", Type: telegram.DataType{Types: []string{"Integer"}}, Required: true},
+			{Name: "sticker", Type: telegram.DataType{Types: []string{"InputFile"}}, Required: true},
+		}},
+	}
+	core, logs := observer.New(zap.WarnLevel)
+	spec, err := NewWithType(zap.New(core), "7.0", uploadTypes(), methods, "botapi").
+		WithOptions(Options{HTTPMethods: []string{"get", "post"}}).
+		Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	webhook := spec.Paths["/setWebhook"]
+	if webhook.Get == nil || len(webhook.Get.Parameters) != 1 || webhook.Get.Parameters[0].Name != "url" {
+		t.Errorf("setWebhook GET = %+v, want only the url parameter", webhook.Get)
+	}
+	upload := spec.Paths["/uploadStickerFile"]
+	if upload.Get != nil {
+		t.Errorf("uploadStickerFile must not have a GET operation, got %+v", upload.Get)
+	}
+	if upload.Post == nil || upload.Post.OperationID != "uploadStickerFile" {
+		t.Errorf("uploadStickerFile POST = %+v, want the plain operation ID", upload.Post)
+	}
+	warnings := logs.FilterMessage("skipping GET operation: required parameters can only be uploaded as files").All()
+	if len(warnings) != 1 || warnings[0].ContextMap()["method"] != "uploadStickerFile" {
+		t.Errorf("warnings = %+v, want one for uploadStickerFile", logs.All())
+	}
+
+	getOnly, err := NewWithType(zap.NewNop(), "7.0", uploadTypes(), methods, "botapi").
+		WithOptions(Options{HTTPMethods: []string{"get"}}).
+		Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := getOnly.Paths["/uploadStickerFile"]; ok {
+		t.Error("a method without operations must not get a path")
+	}
+}
+
+func TestWithoutInputFile(t *testing.T) {
+	if _, ok := withoutInputFile(openapi.Schema{Ref: inputFileRef}); ok {
+		t.Error("a file-only property must be dropped")
+	}
+	if _, ok := withoutInputFile(openapi.Schema{Type: openapi.Types{"array"}, Items: &openapi.Schema{Ref: inputFileRef}}); ok {
+		t.Error("an array of files must be dropped")
+	}
+	got, ok := withoutInputFile(openapi.Schema{OneOf: []openapi.Schema{{Ref: inputFileRef}, {Type: openapi.Types{"string"}}}})
+	if !ok || !got.Type.Is("string") || got.OneOf != nil {
+		t.Errorf("InputFile or String = %+v, want string", got)
+	}
+}
+
+func TestGenerate_DeprecatedOperation(t *testing.T) {
+	methods := []telegram.Method{{Name: "getChatMembersCount", ReturnTypes: []telegram.ReturnType{{Name: "integer"}}, Deprecated: true, Replacement: "getChatMemberCount"}}
+	spec, err := NewWithType(zap.NewNop(), "7.0", map[string]telegram.Type{}, methods, "botapi").Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := spec.Paths["/getChatMembersCount"].Post
+	if !op.Deprecated || op.XTelegramReplacement != "getChatMemberCount" {
+		t.Errorf("operation = %+v, want deprecated in favour of getChatMemberCount", op)
+	}
+}
+
+func TestGenerate_DeprecatedQueryParameter(t *testing.T) {
+	methods := []telegram.Method{{Name: "sendMessage", ReturnTypes: []telegram.ReturnType{{Name: "Message"}}, Parameters: []telegram.Parameter{
+		{Name: "reply_to_message_id", Type: telegram.DataType{Types: []string{"Integer"}}, Deprecated: true, Replacement: "reply_parameters"},
+	}}}
+	spec, err := NewWithType(zap.NewNop(), "7.0", map[string]telegram.Type{}, methods, "botapi").
+		WithOptions(Options{HTTPMethods: []string{"get", "post"}}).
+		Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := spec.Paths["/sendMessage"]
+	if p := path.Get.Parameters[0]; !p.Deprecated || p.XTelegramReplacement != "reply_parameters" {
+		t.Errorf("query parameter = %+v, want deprecated in favour of reply_parameters", p)
+	}
+	if p := path.Post.RequestBody.Content[contentTypeJSON].Schema.Properties["reply_to_message_id"]; p.XTelegramReplacement != "reply_parameters" {
+		t.Errorf("body property = %+v, want deprecated in favour of reply_parameters", p)
+	}
+}
@@ -1,9 +1,12 @@
 package generator
 
 import (
+	"context"
 	"reflect"
+	"strings"
 	"testing"
 
+	"github.com/superboomer/tg-spec-cli/internal/openapi"
 	"github.com/superboomer/tg-spec-cli/internal/telegram"
 	"go.uber.org/zap"
 )
@@ -90,21 +93,21 @@ func TestConvertDataTypeToProperty(t *testing.T) {
 
 	t.Run("empty types defaults to object", func(t *testing.T) {
 		got := g.convertDataTypeToProperty(telegram.DataType{})
-		if got.Type != "object" {
+		if !got.Type.Is("object") {
 			t.Errorf("expected type object, got %+v", got)
 		}
 	})
 
 	t.Run("single primitive", func(t *testing.T) {
 		got := g.convertDataTypeToProperty(telegram.DataType{Types: []string{"String"}})
-		if got.Type != "string" || got.Ref != "" {
+		if !got.Type.Is("string") || got.Ref != "" {
 			t.Errorf("expected {type:string}, got %+v", got)
 		}
 	})
 
 	t.Run("single ref", func(t *testing.T) {
 		got := g.convertDataTypeToProperty(telegram.DataType{Types: []string{"User"}})
-		if got.Ref != "#/components/schemas/User" || got.Type != "" {
+		if got.Ref != "#/components/schemas/User" || len(got.Type) != 0 {
 			t.Errorf("expected {$ref:User}, got %+v", got)
 		}
 	})
@@ -114,38 +117,38 @@ func TestConvertDataTypeToProperty(t *testing.T) {
 		if len(got.OneOf) != 2 {
 			t.Fatalf("expected oneOf of 2, got %+v", got)
 		}
-		if got.OneOf[0].Type != "integer" || got.OneOf[1].Type != "string" {
+		if !got.OneOf[0].Type.Is("integer") || !got.OneOf[1].Type.Is("string") {
 			t.Errorf("unexpected oneOf contents: %+v", got.OneOf)
 		}
 	})
 
 	t.Run("array of primitive", func(t *testing.T) {
 		got := g.convertDataTypeToProperty(telegram.DataType{Types: []string{"String"}, IsArray: true, ArrayDepth: 1})
-		if got.Type != "array" || got.Items == nil || got.Items.Type != "string" {
+		if !got.Type.Is("array") || got.Items == nil || !got.Items.Type.Is("string") {
 			t.Errorf("expected array of string, got %+v", got)
 		}
 	})
 
 	t.Run("array of ref", func(t *testing.T) {
 		got := g.convertDataTypeToProperty(telegram.DataType{Types: []string{"User"}, IsArray: true, ArrayDepth: 1})
-		if got.Type != "array" || got.Items == nil || got.Items.Ref != "#/components/schemas/User" {
+		if !got.Type.Is("array") || got.Items == nil || got.Items.Ref != "#/components/schemas/User" {
 			t.Errorf("expected array of $ref User, got %+v", got)
 		}
 	})
 
 	t.Run("array of multiple types -> items oneOf", func(t *testing.T) {
 		got := g.convertDataTypeToProperty(telegram.DataType{Types: []string{"Integer", "String"}, IsArray: true, ArrayDepth: 1})
-		if got.Type != "array" || got.Items == nil || len(got.Items.OneOf) != 2 {
+		if !got.Type.Is("array") || got.Items == nil || len(got.Items.OneOf) != 2 {
 			t.Errorf("expected array of oneOf, got %+v", got)
 		}
 	})
 
 	t.Run("nested array depth 2", func(t *testing.T) {
 		got := g.convertDataTypeToProperty(telegram.DataType{Types: []string{"String"}, IsArray: true, ArrayDepth: 2})
-		if got.Type != "array" || got.Items == nil || got.Items.Type != "array" {
+		if !got.Type.Is("array") || got.Items == nil || !got.Items.Type.Is("array") {
 			t.Fatalf("expected array of array, got %+v", got)
 		}
-		if got.Items.Items == nil || got.Items.Items.Type != "string" {
+		if got.Items.Items == nil || !got.Items.Items.Type.Is("string") {
 			t.Errorf("expected inner items string, got %+v", got.Items.Items)
 		}
 	})
@@ -157,7 +160,7 @@ func TestConvertTypesToProperties(t *testing.T) {
 	if len(got) != 2 {
 		t.Fatalf("expected 2 properties, got %d", len(got))
 	}
-	if got[0].Type != "string" {
+	if !got[0].Type.Is("string") {
 		t.Errorf("expected string property, got %+v", got[0])
 	}
 	if got[1].Ref != "#/components/schemas/User" {
@@ -170,18 +173,37 @@ func TestConvertMethodReturnType(t *testing.T) {
 
 	t.Run("array return type", func(t *testing.T) {
 		got := g.convertMethodReturnType(telegram.ReturnType{Name: "Update", IsArray: true})
-		if got.Type != "array" || got.Items == nil || got.Items.Ref != "#/components/schemas/Update" {
+		if !got.Type.Is("array") || got.Items == nil || got.Items.Ref != "#/components/schemas/Update" {
 			t.Errorf("expected array of $ref Update, got %+v", got)
 		}
 	})
 
+	t.Run("alternatives", func(t *testing.T) {
+		got := g.convertMethodReturnTypes([]telegram.ReturnType{{Name: "Message"}, {Name: "boolean"}})
+		if len(got.OneOf) != 2 || got.OneOf[0].Ref != "#/components/schemas/Message" || !got.OneOf[1].Type.Is("boolean") {
+			t.Errorf("expected oneOf Message/boolean, got %+v", got)
+		}
+	})
+
+	t.Run("nested array", func(t *testing.T) {
+		got := g.convertMethodReturnType(telegram.ReturnType{Name: "integer", IsArray: true, ArrayDepth: 2})
+		if !got.Type.Is("array") || !got.Items.Type.Is("array") || !got.Items.Items.Type.Is("integer") {
+			t.Errorf("expected array of array of integer, got %+v", got)
+		}
+	})
+
+	t.Run("none", func(t *testing.T) {
+		if got := g.convertMethodReturnTypes(nil); !got.Type.Is("boolean") {
+			t.Errorf("expected boolean, got %+v", got)
+		}
+	})
+
 	tests := []struct {
 		name     string
 		in       telegram.ReturnType
 		wantType string
 		wantRef  string
 	}{
-		{"empty -> boolean", telegram.ReturnType{Name: ""}, "boolean", ""},
 		{"integer", telegram.ReturnType{Name: "integer"}, "integer", ""},
 		{"boolean", telegram.ReturnType{Name: "boolean"}, "boolean", ""},
 		{"String", telegram.ReturnType{Name: "String"}, "string", ""},
@@ -192,7 +214,7 @@ func TestConvertMethodReturnType(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := g.convertMethodReturnType(tt.in)
-			if got.Type != tt.wantType || got.Ref != tt.wantRef {
+			if strings.Join(got.Type, ",") != tt.wantType || got.Ref != tt.wantRef {
 				t.Errorf("got %+v, want type=%q ref=%q", got, tt.wantType, tt.wantRef)
 			}
 		})
@@ -225,3 +247,211 @@ func TestDetectUnionTypes(t *testing.T) {
 		t.Errorf("variants = %v, want %v", variants, want)
 	}
 }
+
+func TestApplyEnum(t *testing.T) {
+	p := openapi.Schema{Type: openapi.Types{"string"}}
+	applyEnum(&p, []string{"a", "b"})
+	if !reflect.DeepEqual(p.Enum, []any{"a", "b"}) {
+		t.Errorf("Enum = %v, want [a b]", p.Enum)
+	}
+
+	arr := openapi.Schema{Type: openapi.Types{"array"}, Items: &openapi.Schema{Type: openapi.Types{"string"}}}
+	applyEnum(&arr, []string{"x"})
+	if arr.Enum != nil || !reflect.DeepEqual(arr.Items.Enum, []any{"x"}) {
+		t.Errorf("enum should be placed on array items, got %+v / %+v", arr, arr.Items)
+	}
+
+	ref := openapi.Schema{Ref: "#/components/schemas/User"}
+	applyEnum(&ref, []string{"x"})
+	if ref.Enum != nil {
+		t.Errorf("non-string property must not get an enum, got %v", ref.Enum)
+	}
+}
+
+func TestUnionDiscriminator(t *testing.T) {
+	variant := func(name, status string) telegram.Type {
+		return telegram.Type{Name: name, Fields: []telegram.Field{
+			{Name: "status", Type: []string{"String"}, Const: status},
+			{Name: "user", Type: []string{"User"}},
+		}}
+	}
+	g := NewWithType(zap.NewNop(), "1.0", map[string]telegram.Type{
+		"chatmemberowner":  variant("ChatMemberOwner", "creator"),
+		"chatmembermember": variant("ChatMemberMember", "member"),
+		"chatmemberother":  variant("ChatMemberOther", "member"),
+		"plain":            {Name: "Plain", Fields: []telegram.Field{{Name: "id", Type: []string{"Integer"}}}},
+	}, nil, "botapi")
+
+	d := g.unionDiscriminator("ChatMember", []string{"ChatMemberOwner", "ChatMemberMember"})
+	if d == nil {
+		t.Fatal("expected a discriminator")
+	}
+	if d.PropertyName != "status" {
+		t.Errorf("PropertyName = %q, want status", d.PropertyName)
+	}
+	want := map[string]string{
+		"creator": "#/components/schemas/ChatMemberOwner",
+		"member":  "#/components/schemas/ChatMemberMember",
+	}
+	if !reflect.DeepEqual(d.Mapping, want) {
+		t.Errorf("Mapping = %v, want %v", d.Mapping, want)
+	}
+
+	d = g.unionDiscriminator("X", []string{"ChatMemberOwner", "ChatMemberMember", "ChatMemberOther"})
+	if d == nil || d.PropertyName != "status" {
+		t.Fatalf("variants sharing a value must still produce a discriminator, got %+v", d)
+	}
+	if want := map[string]string{"creator": "#/components/schemas/ChatMemberOwner"}; !reflect.DeepEqual(d.Mapping, want) {
+		t.Errorf("Mapping = %v, want only the unique value %v", d.Mapping, want)
+	}
+	if d := g.unionDiscriminator("X", []string{"ChatMemberMember", "ChatMemberOther"}); d == nil || d.Mapping != nil {
+		t.Errorf("no unique value must give a discriminator without mapping, got %+v", d)
+	}
+	if d := g.unionDiscriminator("X", []string{"ChatMemberOwner", "Plain"}); d != nil {
+		t.Errorf("variant without the constant must not produce a discriminator, got %+v", d)
+	}
+	if d := g.unionDiscriminator("X", []string{"ChatMemberOwner", "Missing"}); d != nil {
+		t.Errorf("unknown variant must not produce a discriminator, got %+v", d)
+	}
+}
+
+// TestUnionDiscriminator_InlineQueryResult checks the union whose variants
+// share "type" values: cached and uploaded results of the same kind.
+func TestUnionDiscriminator_InlineQueryResult(t *testing.T) {
+	variant := func(name, typ, file string) telegram.Type {
+		return telegram.Type{Name: name, Fields: []telegram.Field{
+			{Name: "type", Type: []string{"String"}, Required: true, Const: typ},
+			{Name: "id", Type: []string{"String"}, Required: true},
+			{Name: file, Type: []string{"String"}, Required: true},
+		}}
+	}
+	types := map[string]telegram.Type{
+		"inlinequeryresult": {Name: "InlineQueryResult", Description: "This object represents one result of an inline query. It can be one of\n" +
+			"- InlineQueryResultArticle\n- InlineQueryResultPhoto\n- InlineQueryResultCachedPhoto\n"},
+		"inlinequeryresultarticle":     variant("InlineQueryResultArticle", "article", "title"),
+		"inlinequeryresultphoto":       variant("InlineQueryResultPhoto", "photo", "photo_url"),
+		"inlinequeryresultcachedphoto": variant("InlineQueryResultCachedPhoto", "photo", "photo_file_id"),
+	}
+	spec, err := NewWithType(zap.NewNop(), "1.0", types, nil, "botapi").Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	union := spec.Components.Schemas["InlineQueryResult"]
+	if len(union.OneOf) != 3 {
+		t.Fatalf("OneOf = %+v, want 3 variants", union.OneOf)
+	}
+	d := union.Discriminator
+	if d == nil || d.PropertyName != "type" {
+		t.Fatalf("Discriminator = %+v, want propertyName type", d)
+	}
+	if want := map[string]string{"article": "#/components/schemas/InlineQueryResultArticle"}; !reflect.DeepEqual(d.Mapping, want) {
+		t.Errorf("Mapping = %v, want %v", d.Mapping, want)
+	}
+	for _, name := range []string{"InlineQueryResultPhoto", "InlineQueryResultCachedPhoto"} {
+		if c := spec.Components.Schemas[name].Properties["type"].Const; c != "photo" {
+			t.Errorf("%s.type const = %v, want photo", name, c)
+		}
+	}
+}
+
+func TestApplyConstraints(t *testing.T) {
+	one, hundred := 1, 100
+	lo, hi := 1.0, 100.0
+
+	p := openapi.Schema{Type: openapi.Types{"integer"}}
+	applyConstraints(&p, &telegram.Constraints{Minimum: &lo, Maximum: &hi})
+	if p.Minimum == nil || *p.Minimum != 1 || p.Maximum == nil || *p.Maximum != 100 {
+		t.Errorf("expected minimum/maximum 1..100, got %+v", p)
+	}
+
+	arr := openapi.Schema{Type: openapi.Types{"array"}, Items: &openapi.Schema{Type: openapi.Types{"string"}}}
+	applyConstraints(&arr, &telegram.Constraints{MinItems: &one, MaxItems: &hundred, MaxLength: &hundred})
+	if arr.MinItems == nil || *arr.MaxItems != 100 {
+		t.Errorf("item counts belong on the array, got %+v", arr)
+	}
+	if arr.Items.MaxLength == nil || arr.MaxLength != nil {
+		t.Errorf("length limits belong on the items, got %+v / %+v", arr, arr.Items)
+	}
+
+	untouched := openapi.Schema{Type: openapi.Types{"string"}}
+	applyConstraints(&untouched, nil)
+	if untouched.MaxLength != nil {
+		t.Errorf("nil constraints must not change the property, got %+v", untouched)
+	}
+}
+
+func TestConvertTypeToProperty_Formats(t *testing.T) {
+	g := newTestGen()
+	if p := g.convertTypeToProperty("Float"); !p.Type.Is("number") || p.Format != "double" {
+		t.Errorf("Float = %+v, want number/double", p)
+	}
+	if p := g.convertTypeToProperty("Integer"); p.Format != "" {
+		t.Errorf("Integer must not get a format by default, got %q", p.Format)
+	}
+}
+
+func TestApplyIntegerFormat(t *testing.T) {
+	oneOf := openapi.Schema{OneOf: []openapi.Schema{{Type: openapi.Types{"integer"}}, {Type: openapi.Types{"string"}}}}
+	applyIntegerFormat(&oneOf, true)
+	if oneOf.OneOf[0].Format != "int64" || oneOf.OneOf[1].Format != "" {
+		t.Errorf("only the integer variant should be int64, got %+v", oneOf.OneOf)
+	}
+
+	arr := openapi.Schema{Type: openapi.Types{"array"}, Items: &openapi.Schema{Type: openapi.Types{"integer"}}}
+	applyIntegerFormat(&arr, true)
+	if arr.Items.Format != "int64" {
+		t.Errorf("array items format = %q, want int64", arr.Items.Format)
+	}
+
+	lo, hi := 1.0, 100.0
+	ranged := openapi.Schema{Type: openapi.Types{"integer"}, Minimum: &lo, Maximum: &hi}
+	applyIntegerFormat(&ranged, false)
+	if ranged.Format != "int32" {
+		t.Errorf("ranged integer format = %q, want int32", ranged.Format)
+	}
+
+	plain := openapi.Schema{Type: openapi.Types{"integer"}}
+	applyIntegerFormat(&plain, false)
+	if plain.Format != "int32" {
+		t.Errorf("ordinary integer format = %q, want int32", plain.Format)
+	}
+
+	big := 1e12
+	wide := openapi.Schema{Type: openapi.Types{"integer"}, Maximum: &big}
+	applyIntegerFormat(&wide, false)
+	if wide.Format != "int64" {
+		t.Errorf("integer with a range beyond 32 bits format = %q, want int64", wide.Format)
+	}
+}
+
+func TestInt64Names(t *testing.T) {
+	g := NewWithType(zap.NewNop(), "1.0", map[string]telegram.Type{
+		"chat": {Name: "Chat", Fields: []telegram.Field{{Name: "id", Type: []string{"Integer"}, Int64: true}}},
+		"message": {Name: "Message", Fields: []telegram.Field{
+			{Name: "migrate_to_chat_id", Type: []string{"Integer"}, Int64: true},
+			{Name: "date", Type: []string{"Integer"}},
+		}},
+		// Only identifiers carry over: a flagged "amount" says nothing
+		// about other amounts, and Gift has no "_id" name to infer.
+		"gift": {Name: "Gift", Fields: []telegram.Field{
+			{Name: "amount", Type: []string{"Integer"}, Int64: true},
+		}},
+		"story": {Name: "Story", Fields: []telegram.Field{{Name: "id", Type: []string{"Integer"}}}},
+	}, []telegram.Method{{Name: "banChatMember", Parameters: []telegram.Parameter{
+		{Name: "user_id", Type: telegram.DataType{Types: []string{"Integer"}}, Int64: true},
+	}}}, "botapi")
+
+	want := map[string]bool{"chat_id": true, "migrate_to_chat_id": true, "user_id": true}
+	if got := g.int64Names(); !reflect.DeepEqual(got, want) {
+		t.Errorf("int64Names() = %v, want %v", got, want)
+	}
+}
+
+func TestToSnakeCase(t *testing.T) {
+	for in, want := range map[string]string{"Chat": "chat", "ChatMember": "chat_member", "User": "user"} {
+		if got := toSnakeCase(in); got != want {
+			t.Errorf("toSnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package generator
+
+import (
+	"github.com/superboomer/tg-spec-cli/internal/openapi"
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+)
+
+// externalDocs links an element to its heading on the documentation page,
+// or returns nil when the element has no anchor.
+func (g *Generator) externalDocs(anchor string) *openapi.ExternalDocs {
+	if anchor == "" {
+		return nil
+	}
+	return &openapi.ExternalDocs{URL: g.docsPage() + "#" + anchor}
+}
+
+// docsPage returns the documentation page set with WithDocsURL, or the
+// official one for the API type.
+func (g *Generator) docsPage() string {
+	switch {
+	case g.docsURL != "":
+		return g.docsURL
+	case g.typeFlag == "gateway":
+		return telegram.GatewayDocsURL
+	default:
+		return telegram.DefaultDocsURL
+	}
+}
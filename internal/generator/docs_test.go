@@ -0,0 +1,60 @@
+package generator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/superboomer/tg-spec-cli/internal/openapi"
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+	"go.uber.org/zap"
+)
+
+func TestGenerate_ExternalDocs(t *testing.T) {
+	types := map[string]telegram.Type{
+		"user":            {Name: "User", Anchor: "user", Fields: []telegram.Field{{Name: "id", Type: []string{"Integer"}, Required: true}}},
+		"unanchored":      {Name: "Unanchored", Fields: []telegram.Field{{Name: "id", Type: []string{"Integer"}, Required: true}}},
+		"chatmember":      {Name: "ChatMember", Anchor: "chatmember", Description: "It can be one of\n- ChatMemberOwner\n"},
+		"chatmemberowner": {Name: "ChatMemberOwner", Anchor: "chatmemberowner", Fields: []telegram.Field{{Name: "status", Type: []string{"String"}, Required: true, Const: "creator"}}},
+	}
+	methods := []telegram.Method{{Name: "getMe", Anchor: "getme", ReturnTypes: []telegram.ReturnType{{Name: "User"}}}}
+
+	tests := []struct {
+		name    string
+		typ     string
+		docsURL string
+		want    string
+	}{
+		{name: "default bot api page", typ: "botapi", want: telegram.DefaultDocsURL},
+		{name: "default gateway page", typ: "gateway", want: telegram.GatewayDocsURL},
+		{name: "custom page", typ: "botapi", docsURL: "https://example.com/docs", want: "https://example.com/docs"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := NewWithType(zap.NewNop(), "7.0", types, methods, tt.typ).WithDocsURL(tt.docsURL).Generate(context.Background())
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			op := spec.Paths["/getMe"].Post
+			if want := (&openapi.ExternalDocs{URL: tt.want + "#getme"}); !reflect.DeepEqual(op.ExternalDocs, want) {
+				t.Errorf("getMe externalDocs = %+v, want %+v", op.ExternalDocs, want)
+			}
+			if op.XTelegramAnchor != "getme" {
+				t.Errorf("getMe x-telegram-anchor = %q, want getme", op.XTelegramAnchor)
+			}
+			for name, anchor := range map[string]string{"User": "user", "ChatMember": "chatmember"} {
+				schema := spec.Components.Schemas[name]
+				if schema.ExternalDocs == nil || schema.ExternalDocs.URL != tt.want+"#"+anchor {
+					t.Errorf("%s externalDocs = %+v, want %s#%s", name, schema.ExternalDocs, tt.want, anchor)
+				}
+				if schema.XTelegramAnchor != anchor {
+					t.Errorf("%s x-telegram-anchor = %q, want %q", name, schema.XTelegramAnchor, anchor)
+				}
+			}
+			if docs := spec.Components.Schemas["Unanchored"].ExternalDocs; docs != nil {
+				t.Errorf("a type without an anchor must not get externalDocs, got %+v", docs)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/superboomer/tg-spec-cli/internal/openapi"
+)
+
+// errorSchemaName is the component schema of the {ok: false, …} envelope
+// the Bot API answers failed requests with.
+const errorSchemaName = "ErrorResponse"
+
+// errorResponse is a documented failure status, shared as
+// components.responses[name].
+type errorResponse struct {
+	status      string
+	name        string
+	description string
+}
+
+var errorResponses = []errorResponse{
+	{"400", "BadRequest", "Bad request, e.g. invalid or missing parameters. When a group has been migrated to a supergroup, parameters.migrate_to_chat_id holds the identifier of the supergroup."},
+	{"401", "Unauthorized", "The bot token is invalid."},
+	{"403", "Forbidden", "The bot is not allowed to perform the request, e.g. it was blocked by the user or removed from the chat."},
+	{"404", "NotFound", "The method doesn't exist or the bot token is invalid."},
+	{"409", "Conflict", "The request conflicts with another one, e.g. getUpdates while a webhook is set."},
+	{"429", "TooManyRequests", "Flood control exceeded. Repeat the request after parameters.retry_after seconds."},
+}
+
+// addErrorComponents adds the error envelope schema and the shared error
+// responses to the Bot API spec.
+func (g *Generator) addErrorComponents(components *openapi.Components) {
+	components.Schemas[errorSchemaName] = g.errorSchema()
+
+	components.Responses = make(map[string]openapi.Response, len(errorResponses))
+	for _, e := range errorResponses {
+		schema := openapi.Schema{Ref: "#/components/schemas/" + errorSchemaName}
+		if e.status == "429" {
+			// The envelope is the same, but parameters.retry_after is
+			// always present.
+			schema = openapi.Schema{
+				AllOf: []openapi.Schema{
+					{Ref: "#/components/schemas/" + errorSchemaName},
+					{
+						Type: openapi.Types{"object"},
+						Properties: map[string]openapi.Schema{
+							"error_code": {Type: openapi.Types{"integer"}, Const: 429},
+							"parameters": {Type: openapi.Types{"object"}, Required: []string{"retry_after"}},
+						},
+						Required: []string{"parameters"},
+					},
+				},
+			}
+		}
+		components.Responses[e.name] = openapi.Response{
+			Description: e.description,
+			Content:     map[string]openapi.MediaType{contentTypeJSON: {Schema: &schema}},
+		}
+	}
+}
+
+// errorSchema returns the {ok: false, error_code, description, parameters}
+// envelope. parameters refers to the parsed ResponseParameters type, or is
+// described inline if the documentation didn't provide it.
+func (g *Generator) errorSchema() openapi.Schema {
+	parameters := openapi.Schema{Ref: "#/components/schemas/ResponseParameters"}
+	if _, ok := g.types["responseparameters"]; !ok {
+		g.log.Warn("ResponseParameters type not found; describing error parameters inline")
+		parameters = openapi.Schema{
+			Type: openapi.Types{"object"},
+			Properties: map[string]openapi.Schema{
+				"migrate_to_chat_id": {Type: openapi.Types{"integer"}, Format: "int64", Description: "The group has been migrated to a supergroup with the specified identifier."},
+				"retry_after":        {Type: openapi.Types{"integer"}, Format: "int32", Description: "In case of exceeding flood control, the number of seconds left to wait before the request can be repeated."},
+			},
+		}
+	}
+	parameters.Description = "Information on how the request can be repeated, if available."
+
+	return openapi.Schema{
+		Type:        openapi.Types{"object"},
+		Description: "Response of a failed request.",
+		Properties: map[string]openapi.Schema{
+			"ok":          {Type: openapi.Types{"boolean"}, Const: false, Description: "Always false for failed requests"},
+			"error_code":  {Type: openapi.Types{"integer"}, Format: "int32", Description: "HTTP status code of the error; may change in the future"},
+			"description": {Type: openapi.Types{"string"}, Description: "Human-readable description of the error"},
+			"parameters":  parameters,
+		},
+		Required: []string{"ok", "error_code", "description"},
+	}
+}
+
+// errorResponseRefs returns the references to the shared error responses,
+// keyed by status code.
+func errorResponseRefs() map[string]openapi.Response {
+	refs := make(map[string]openapi.Response, len(errorResponses))
+	for _, e := range errorResponses {
+		refs[e.status] = openapi.Response{Ref: fmt.Sprintf("#/components/responses/%s", e.name)}
+	}
+	return refs
+}
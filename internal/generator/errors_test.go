@@ -0,0 +1,98 @@
+package generator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+	"go.uber.org/zap"
+)
+
+func TestGenerate_ErrorResponses(t *testing.T) {
+	types := sampleTypes()
+	types["responseparameters"] = telegram.Type{
+		Name: "ResponseParameters",
+		Fields: []telegram.Field{
+			{Name: "migrate_to_chat_id", Type: []string{"Integer"}, Int64: true},
+			{Name: "retry_after", Type: []string{"Integer"}},
+		},
+	}
+	spec, err := NewWithType(zap.NewNop(), "7.0", types, sampleMethods(), "botapi").Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	envelope, ok := spec.Components.Schemas[errorSchemaName]
+	if !ok {
+		t.Fatal("error envelope schema missing")
+	}
+	if ref := envelope.Properties["parameters"].Ref; ref != "#/components/schemas/ResponseParameters" {
+		t.Errorf("parameters = %q, want a reference to ResponseParameters", ref)
+	}
+	if c := envelope.Properties["ok"].Const; c != false {
+		t.Errorf("ok const = %v, want false", c)
+	}
+
+	for _, e := range errorResponses {
+		if _, ok := spec.Components.Responses[e.name]; !ok {
+			t.Errorf("components.responses.%s missing", e.name)
+		}
+	}
+	tooMany := spec.Components.Responses["TooManyRequests"].Content[contentTypeJSON].Schema
+	if len(tooMany.AllOf) != 2 || tooMany.AllOf[1].Properties["parameters"].Required[0] != "retry_after" {
+		t.Errorf("429 must require parameters.retry_after, got %+v", tooMany)
+	}
+
+	responses := spec.Paths["/sendMessage"].Post.Responses
+	for _, status := range []string{"200", "400", "401", "403", "404", "409", "429"} {
+		if _, ok := responses[status]; !ok {
+			t.Errorf("sendMessage has no %s response", status)
+		}
+	}
+	if ref := responses["429"].Ref; ref != "#/components/responses/TooManyRequests" {
+		t.Errorf("429 = %q, want a reference to the shared response", ref)
+	}
+}
+
+func TestErrorSchema_InlineParameters(t *testing.T) {
+	schema := newTestGen().errorSchema()
+	params := schema.Properties["parameters"]
+	if params.Ref != "" {
+		t.Fatalf("expected inline parameters without ResponseParameters, got ref %q", params.Ref)
+	}
+	if f := params.Properties["migrate_to_chat_id"].Format; f != "int64" {
+		t.Errorf("migrate_to_chat_id format = %q, want int64", f)
+	}
+	if _, ok := params.Properties["retry_after"]; !ok {
+		t.Error("retry_after missing from inline parameters")
+	}
+}
+
+func TestGenerate_GatewayHasNoBotAPIErrors(t *testing.T) {
+	spec, err := NewWithType(zap.NewNop(), "1.0", map[string]telegram.Type{}, sampleMethods(), "gateway").Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.Components.Responses != nil {
+		t.Error("the gateway API uses a different error format")
+	}
+	if _, ok := spec.Paths["/sendMessage"].Post.Responses["429"]; ok {
+		t.Error("gateway operations must not reference Bot API error responses")
+	}
+}
+
+func TestGenerate_EmptyTypeIsBotAPI(t *testing.T) {
+	spec, err := NewWithType(zap.NewNop(), "7.0", sampleTypes(), sampleMethods(), "").Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.Info.Title != "Telegram Bot API" {
+		t.Errorf("title = %q, want the Bot API", spec.Info.Title)
+	}
+	if _, ok := spec.Components.Schemas[errorSchemaName]; !ok {
+		t.Error("an empty type must get the Bot API error envelope")
+	}
+	if _, ok := spec.Paths["/sendMessage"].Post.Responses["429"]; !ok {
+		t.Error("an empty type must get the Bot API error responses")
+	}
+}
@@ -1,9 +1,12 @@
 package generator
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/superboomer/tg-spec-cli/internal/openapi"
@@ -19,14 +22,23 @@ func sampleTypes() map[string]telegram.Type {
 			Name:        "User",
 			Description: "This object represents a user.",
 			Fields: []telegram.Field{
-				{Name: "id", Type: []string{"Integer"}, Description: "Unique id", Required: true},
+				{Name: "id", Type: []string{"Integer"}, Description: "Unique id", Required: true, Int64: true},
 				{Name: "username", Type: []string{"String"}, Description: "Optional. Username", Required: false},
+				{Name: "kind", Type: []string{"String"}, Required: true, Enum: []string{"bot", "human"}},
 			},
 		},
 		"chatmember": {
 			Name:        "ChatMember",
 			Description: "This object contains info about a chat member. It can be one of\n- ChatMemberOwner\n- ChatMemberMember\n",
 		},
+		"chatmemberowner": {
+			Name:   "ChatMemberOwner",
+			Fields: []telegram.Field{{Name: "status", Type: []string{"String"}, Required: true, Const: "creator"}},
+		},
+		"chatmembermember": {
+			Name:   "ChatMemberMember",
+			Fields: []telegram.Field{{Name: "status", Type: []string{"String"}, Required: true, Const: "member"}},
+		},
 	}
 }
 
@@ -35,15 +47,18 @@ func sampleMethods() []telegram.Method {
 		{
 			Name:        "getMe",
 			Description: "Returns basic information about the bot.",
-			ReturnType:  telegram.ReturnType{Name: "User"},
+			ReturnTypes: []telegram.ReturnType{{Name: "User"}},
 		},
 		{
 			Name:        "sendMessage",
 			Description: "Use this method to send text messages.",
-			ReturnType:  telegram.ReturnType{Name: "Message"},
+			ReturnTypes: []telegram.ReturnType{{Name: "Message"}},
 			Parameters: []telegram.Parameter{
 				{Name: "chat_id", Type: telegram.DataType{Types: []string{"Integer", "String"}}, Required: true},
 				{Name: "text", Type: telegram.DataType{Types: []string{"String"}}, Required: true},
+				{Name: "parse_mode", Type: telegram.DataType{Types: []string{"String"}}, Enum: []string{"MarkdownV2", "HTML"}},
+				{Name: "disable_notification", Type: telegram.DataType{Types: []string{"Boolean"}}, Default: false},
+				{Name: "reply_to_message_id", Type: telegram.DataType{Types: []string{"Integer"}}, Deprecated: true, Replacement: "reply_parameters"},
 			},
 		},
 	}
@@ -51,7 +66,7 @@ func sampleMethods() []telegram.Method {
 
 func TestGenerate_BotAPI(t *testing.T) {
 	gen := NewWithType(zap.NewNop(), "7.0", sampleTypes(), sampleMethods(), "botapi")
-	spec, err := gen.Generate()
+	spec, err := gen.Generate(context.Background())
 	if err != nil {
 		t.Fatalf("Generate() error = %v", err)
 	}
@@ -68,7 +83,7 @@ func TestGenerate_BotAPI(t *testing.T) {
 	if len(spec.Servers) != 2 {
 		t.Errorf("expected 2 servers for botapi, got %d", len(spec.Servers))
 	}
-	if spec.Servers[0].Variables == nil || spec.Servers[0].Variables.Token == nil {
+	if spec.Servers[0].Variables["token"].Default == "" {
 		t.Error("botapi server should have a token variable")
 	}
 	if spec.Security != nil {
@@ -83,8 +98,11 @@ func TestGenerate_BotAPI(t *testing.T) {
 	if !ok {
 		t.Fatal("User schema missing")
 	}
-	if len(user.Required) != 1 || user.Required[0] != "id" {
-		t.Errorf("User.Required = %v, want [id]", user.Required)
+	if len(user.Required) != 2 || user.Required[0] != "id" {
+		t.Errorf("User.Required = %v, want [id kind]", user.Required)
+	}
+	if enum := user.Properties["kind"].Enum; len(enum) != 2 || enum[0] != "bot" {
+		t.Errorf("User.kind enum = %v, want [bot human]", enum)
 	}
 	if _, ok := user.Properties["username"]; !ok {
 		t.Error("User.Properties should contain username")
@@ -95,23 +113,41 @@ func TestGenerate_BotAPI(t *testing.T) {
 	if !ok {
 		t.Fatal("ChatMember schema missing")
 	}
-	if cm.Type != "" {
-		t.Errorf("union schema must not set type, got %q", cm.Type)
+	if len(cm.Type) != 0 {
+		t.Errorf("union schema must not set type, got %v", cm.Type)
 	}
 	if len(cm.OneOf) != 2 {
 		t.Errorf("ChatMember.OneOf = %v, want 2 variants", cm.OneOf)
 	}
+	if cm.Discriminator == nil || cm.Discriminator.PropertyName != "status" || len(cm.Discriminator.Mapping) != 2 {
+		t.Errorf("ChatMember.Discriminator = %+v, want status with 2 mappings", cm.Discriminator)
+	}
+	if c := spec.Components.Schemas["ChatMemberOwner"].Properties["status"].Const; c != "creator" {
+		t.Errorf("ChatMemberOwner.status const = %v, want creator", c)
+	}
 
 	// Paths: one POST per method, response includes ok + result.
 	op, ok := spec.Paths["/sendMessage"]
 	if !ok {
 		t.Fatal("/sendMessage path missing")
 	}
-	props := op.Post.RequestBody.Content.Applicationjson.Schema.Properties
+	props := op.Post.RequestBody.Content["application/json"].Schema.Properties
 	if _, ok := props["chat_id"]; !ok {
 		t.Error("sendMessage should expose chat_id parameter")
 	}
-	resp200 := op.Post.Responses["200"].Content.Applicationjson.Schema
+	if enum := props["parse_mode"].Enum; len(enum) != 2 {
+		t.Errorf("parse_mode enum = %v, want 2 values", enum)
+	}
+	if f := spec.Components.Schemas["User"].Properties["id"].Format; f != "int64" {
+		t.Errorf("User.id format = %q, want int64", f)
+	}
+	if p := props["reply_to_message_id"]; !p.Deprecated || p.XTelegramReplacement != "reply_parameters" {
+		t.Errorf("reply_to_message_id = %+v, want deprecated with replacement", p)
+	}
+	if d := props["disable_notification"].Default; d != false {
+		t.Errorf("disable_notification default = %v, want false", d)
+	}
+	resp200 := op.Post.Responses["200"].Content["application/json"].Schema
 	if _, ok := resp200.Properties["ok"]; !ok {
 		t.Error("response schema must include 'ok'")
 	}
@@ -122,7 +158,7 @@ func TestGenerate_BotAPI(t *testing.T) {
 
 func TestGenerate_Gateway(t *testing.T) {
 	gen := NewWithType(zap.NewNop(), "2025", sampleTypes(), sampleMethods(), "gateway")
-	spec, err := gen.Generate()
+	spec, err := gen.Generate(context.Background())
 	if err != nil {
 		t.Fatalf("Generate() error = %v", err)
 	}
@@ -146,7 +182,7 @@ func TestGenerate_Gateway(t *testing.T) {
 func TestGenerate_DefaultTypeFlag(t *testing.T) {
 	// Empty type flag falls through to the botapi branch.
 	gen := NewWithType(zap.NewNop(), "1.0", map[string]telegram.Type{}, nil, "")
-	spec, err := gen.Generate()
+	spec, err := gen.Generate(context.Background())
 	if err != nil {
 		t.Fatalf("Generate() error = %v", err)
 	}
@@ -157,18 +193,35 @@ func TestGenerate_DefaultTypeFlag(t *testing.T) {
 
 func TestGenerate_UnknownType(t *testing.T) {
 	gen := NewWithType(zap.NewNop(), "1.0", map[string]telegram.Type{}, nil, "weird")
-	if _, err := gen.Generate(); err == nil {
+	if _, err := gen.Generate(context.Background()); err == nil {
 		t.Error("Generate() with unknown type should return an error")
 	}
 }
 
+func TestGenerateAndSave_Cancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	gen := NewWithType(zap.NewNop(), "7.0", sampleTypes(), sampleMethods(), "botapi")
+	if _, err := gen.Generate(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Generate() error = %v, want context.Canceled", err)
+	}
+
+	dir := t.TempDir()
+	if err := gen.Save(ctx, &openapi.OpenAPI{OpenAPI: "3.1.0"}, dir); !errors.Is(err, context.Canceled) {
+		t.Errorf("Save() error = %v, want context.Canceled", err)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Errorf("cancelled Save() must not write files, found %v", entries)
+	}
+}
+
 func TestSave(t *testing.T) {
 	gen := NewWithType(zap.NewNop(), "7.0", nil, nil, "botapi")
 	spec := &openapi.OpenAPI{OpenAPI: "3.1.0"}
 
 	t.Run("directory uses default name with version", func(t *testing.T) {
 		dir := t.TempDir()
-		if err := gen.Save(spec, dir); err != nil {
+		if err := gen.Save(context.Background(), spec, dir); err != nil {
 			t.Fatalf("Save() error = %v", err)
 		}
 		assertValidSpec(t, filepath.Join(dir, "openapi-v7.0.json"))
@@ -176,7 +229,7 @@ func TestSave(t *testing.T) {
 
 	t.Run("trailing slash treated as directory", func(t *testing.T) {
 		dir := t.TempDir()
-		if err := gen.Save(spec, dir+"/"); err != nil {
+		if err := gen.Save(context.Background(), spec, dir+"/"); err != nil {
 			t.Fatalf("Save() error = %v", err)
 		}
 		assertValidSpec(t, filepath.Join(dir, "openapi-v7.0.json"))
@@ -184,7 +237,7 @@ func TestSave(t *testing.T) {
 
 	t.Run("explicit file with version placeholder", func(t *testing.T) {
 		dir := t.TempDir()
-		if err := gen.Save(spec, filepath.Join(dir, "bot-api-%v.json")); err != nil {
+		if err := gen.Save(context.Background(), spec, filepath.Join(dir, "bot-api-%v.json")); err != nil {
 			t.Fatalf("Save() error = %v", err)
 		}
 		assertValidSpec(t, filepath.Join(dir, "bot-api-7.0.json"))
@@ -192,7 +245,7 @@ func TestSave(t *testing.T) {
 
 	t.Run("explicit file without placeholder", func(t *testing.T) {
 		dir := t.TempDir()
-		if err := gen.Save(spec, filepath.Join(dir, "exact.json")); err != nil {
+		if err := gen.Save(context.Background(), spec, filepath.Join(dir, "exact.json")); err != nil {
 			t.Fatalf("Save() error = %v", err)
 		}
 		assertValidSpec(t, filepath.Join(dir, "exact.json"))
@@ -200,7 +253,7 @@ func TestSave(t *testing.T) {
 
 	t.Run("creates nested directories", func(t *testing.T) {
 		dir := t.TempDir()
-		if err := gen.Save(spec, filepath.Join(dir, "a", "b", "spec-%v.json")); err != nil {
+		if err := gen.Save(context.Background(), spec, filepath.Join(dir, "a", "b", "spec-%v.json")); err != nil {
 			t.Fatalf("Save() error = %v", err)
 		}
 		assertValidSpec(t, filepath.Join(dir, "a", "b", "spec-7.0.json"))
@@ -209,12 +262,48 @@ func TestSave(t *testing.T) {
 	t.Run("version with comma and spaces is sanitized", func(t *testing.T) {
 		dir := t.TempDir()
 		g := NewWithType(zap.NewNop(), "February 26, 2025", nil, nil, "gateway")
-		if err := g.Save(spec, filepath.Join(dir, "gw-%v.json")); err != nil {
+		if err := g.Save(context.Background(), spec, filepath.Join(dir, "gw-%v.json")); err != nil {
 			t.Fatalf("Save() error = %v", err)
 		}
 		assertValidSpec(t, filepath.Join(dir, "gw-February26-2025.json"))
 	})
 
+	t.Run("yaml format names the default file .yaml", func(t *testing.T) {
+		dir := t.TempDir()
+		g := NewWithType(zap.NewNop(), "7.0", nil, nil, "botapi").WithFormat(openapi.FormatYAML)
+		if err := g.Save(context.Background(), spec, dir); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		data, err := os.ReadFile(filepath.Join(dir, "openapi-v7.0.yaml"))
+		if err != nil {
+			t.Fatalf("expected openapi-v7.0.yaml: %v", err)
+		}
+		if !strings.HasPrefix(string(data), "openapi: 3.1.0\n") {
+			t.Errorf("not a YAML document:\n%s", data)
+		}
+	})
+
+	t.Run("returns error for an unknown format", func(t *testing.T) {
+		g := NewWithType(zap.NewNop(), "7.0", nil, nil, "botapi").WithFormat("xml")
+		if err := g.Save(context.Background(), spec, t.TempDir()); err == nil {
+			t.Error("Save() should fail for an unknown format")
+		}
+	})
+
+	t.Run("leaves no temporary files behind", func(t *testing.T) {
+		dir := t.TempDir()
+		if err := gen.Save(context.Background(), spec, filepath.Join(dir, "spec.json")); err != nil {
+			t.Fatalf("Save() error = %v", err)
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 || entries[0].Name() != "spec.json" {
+			t.Errorf("expected only spec.json in output dir, got %v", entries)
+		}
+	})
+
 	t.Run("returns error when directory cannot be created", func(t *testing.T) {
 		dir := t.TempDir()
 		// Create a regular file, then try to nest a path beneath it.
@@ -222,7 +311,7 @@ func TestSave(t *testing.T) {
 		if err := os.WriteFile(blocker, []byte("x"), 0600); err != nil {
 			t.Fatal(err)
 		}
-		err := gen.Save(spec, filepath.Join(blocker, "sub", "out.json"))
+		err := gen.Save(context.Background(), spec, filepath.Join(blocker, "sub", "out.json"))
 		if err == nil {
 			t.Error("Save() should fail when output directory cannot be created")
 		}
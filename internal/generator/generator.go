@@ -1,11 +1,14 @@
 package generator
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
+	"math"
 	"os"
 	"strings"
+	"unicode"
 
+	"github.com/superboomer/tg-spec-cli/internal/fsutil"
 	"github.com/superboomer/tg-spec-cli/internal/openapi"
 	"github.com/superboomer/tg-spec-cli/internal/telegram"
 
@@ -18,6 +21,10 @@ type Generator struct {
 	types    map[string]telegram.Type
 	methods  []telegram.Method
 	typeFlag string
+	opts     Options
+	sections []telegram.Section
+	docsURL  string
+	format   string
 }
 
 func NewWithType(log *zap.Logger, version string, types map[string]telegram.Type, methods []telegram.Method, typeFlag string) *Generator {
@@ -30,14 +37,49 @@ func NewWithType(log *zap.Logger, version string, types map[string]telegram.Type
 	}
 }
 
-func (g *Generator) Generate() (*openapi.OpenAPI, error) {
+// isBotAPI reports whether g generates the Bot API specification, which is
+// also what an empty type means.
+func (g *Generator) isBotAPI() bool {
+	return g.typeFlag == "botapi" || g.typeFlag == ""
+}
+
+// WithOptions sets the request styles to emit and returns g.
+func (g *Generator) WithOptions(opts Options) *Generator {
+	g.opts = opts
+	return g
+}
+
+// WithSections sets the documentation sections used to describe operation
+// tags and returns g.
+func (g *Generator) WithSections(sections []telegram.Section) *Generator {
+	g.sections = sections
+	return g
+}
+
+// WithDocsURL sets the documentation page externalDocs links point to and
+// returns g. Empty means the official page of the API type.
+func (g *Generator) WithDocsURL(url string) *Generator {
+	g.docsURL = url
+	return g
+}
+
+// WithFormat sets the format Save writes, openapi.FormatJSON (the default) or
+// openapi.FormatYAML, and returns g.
+func (g *Generator) WithFormat(format string) *Generator {
+	g.format = format
+	return g
+}
+
+// Generate builds the OpenAPI document. It stops with ctx's error once ctx is
+// done.
+func (g *Generator) Generate(ctx context.Context) (*openapi.OpenAPI, error) {
 	g.log.Debug("starting OpenAPI generation", zap.String("version", g.version), zap.String("type", g.typeFlag))
 
 	var info openapi.Info
 	var servers []openapi.Server
 
-	switch g.typeFlag {
-	case "gateway":
+	switch {
+	case g.typeFlag == "gateway":
 		info = openapi.Info{
 			Title:       "Telegram Gateway API",
 			Description: `The Gateway API is an HTTP-based interface for phone number verification and related operations. See https://core.telegram.org/gateway/api for details.`,
@@ -49,7 +91,7 @@ func (g *Generator) Generate() (*openapi.OpenAPI, error) {
 				Description: "Telegram Gateway API server",
 			},
 		}
-	case "botapi", "":
+	case g.isBotAPI():
 		info = openapi.Info{
 			Title:       "Telegram Bot API",
 			Description: `The Bot API is an HTTP-based interface created for developers keen on building bots for Telegram.\nTo learn how to create and set up a bot, please consult [Introduction to Bots](https://core.telegram.org/bots) and [Bot FAQ](https://core.telegram.org/bots/faq).`,
@@ -59,8 +101,8 @@ func (g *Generator) Generate() (*openapi.OpenAPI, error) {
 			{
 				URL:         "https://api.telegram.org/bot{token}/",
 				Description: "Production Telegram Bot API server",
-				Variables: &openapi.Variables{
-					Token: &openapi.TokenVariable{
+				Variables: map[string]openapi.ServerVariable{
+					"token": {
 						Description: "Bot token provided by BotFather. It is used to authenticate requests to the Telegram Bot API.",
 						Default:     "123456789:ABCdefGHIjklMNOpqrSTUvwxYZ",
 					},
@@ -69,8 +111,8 @@ func (g *Generator) Generate() (*openapi.OpenAPI, error) {
 			{
 				URL:         "https://api.telegram.org/beta/bot{token}/",
 				Description: "Beta Telegram Bot API server",
-				Variables: &openapi.Variables{
-					Token: &openapi.TokenVariable{
+				Variables: map[string]openapi.ServerVariable{
+					"token": {
 						Description: "Bot token provided by BotFather. It is used to authenticate requests to the Telegram Bot API.",
 						Default:     "123456789:ABCdefGHIjklMNOpqrSTUvwxYZ",
 					},
@@ -117,29 +159,48 @@ func (g *Generator) Generate() (*openapi.OpenAPI, error) {
 		g.log.Debug("union type", zap.String("name", name), zap.Strings("variants", variants))
 	}
 
+	int64Names := g.int64Names()
+
 	for _, t := range g.types {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		g.log.Debug("processing type", zap.String("name", t.Name))
 		if variants, ok := unionTypes[t.Name]; ok {
 			schema := openapi.Schema{
-				OneOf:       []openapi.Property{},
+				OneOf:       []openapi.Schema{},
 				Description: t.Description,
 			}
 			for _, v := range variants {
-				schema.OneOf = append(schema.OneOf, openapi.Property{Ref: fmt.Sprintf("#/components/schemas/%s", v)})
+				schema.OneOf = append(schema.OneOf, openapi.Schema{Ref: fmt.Sprintf("#/components/schemas/%s", v)})
 			}
+			schema.Discriminator = g.unionDiscriminator(t.Name, variants)
+			schema.ExternalDocs, schema.XTelegramAnchor = g.externalDocs(t.Anchor), t.Anchor
+			schema.XTelegramSince = t.Since
 			openAPI.Components.Schemas[t.Name] = schema
 			continue
 		}
 
 		schema := openapi.Schema{
-			Type:        "object",
-			Properties:  make(map[string]openapi.Property),
+			Type:        openapi.Types{"object"},
+			Properties:  make(map[string]openapi.Schema),
 			Description: t.Description,
 		}
+		schema.ExternalDocs, schema.XTelegramAnchor = g.externalDocs(t.Anchor), t.Anchor
+		schema.XTelegramSince = t.Since
 
 		for _, field := range t.Fields {
 			property := g.convertDataTypeToProperty(g.convertStringSliceToDataType(field.Type))
 			property.Description = field.Description
+			applyEnum(&property, field.Enum)
+			applyConstraints(&property, field.Constraints)
+			applyIntegerFormat(&property, field.Int64 || int64Names[field.Name])
+			property.Default = field.Default
+			property.Deprecated, property.XTelegramReplacement = field.Deprecated, field.Replacement
+			property.XTelegramSince = field.Since
+			if field.Const != "" {
+				property.Const = field.Const
+			}
 			schema.Properties[field.Name] = property
 			if field.Required {
 				schema.Required = append(schema.Required, field.Name)
@@ -149,69 +210,55 @@ func (g *Generator) Generate() (*openapi.OpenAPI, error) {
 		openAPI.Components.Schemas[t.Name] = schema
 	}
 
+	if g.isBotAPI() {
+		g.addErrorComponents(&openAPI.Components)
+	}
+
 	for _, m := range g.methods {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		g.log.Debug("processing method", zap.String("name", m.Name))
-		properties := make(map[string]openapi.Property)
-		required := []string{}
+		properties := make(map[string]openapi.Schema)
+		var required []string
 		for _, param := range m.Parameters {
-			properties[param.Name] = g.convertDataTypeToProperty(param.Type)
+			property := g.convertDataTypeToProperty(param.Type)
+			applyEnum(&property, param.Enum)
+			applyConstraints(&property, param.Constraints)
+			applyIntegerFormat(&property, param.Int64 || int64Names[param.Name])
+			property.Default = param.Default
+			property.Deprecated, property.XTelegramReplacement = param.Deprecated, param.Replacement
+			property.XTelegramSince = param.Since
+			properties[param.Name] = property
 			if param.Required {
 				required = append(required, param.Name)
 			}
 		}
 
-		pathItem := openapi.Path{
-			Post: openapi.Operation{
-				Summary:     m.Name,
-				Description: m.Description,
-				OperationID: m.Name,
-				RequestBody: openapi.RequestBody{
-					Content: openapi.MediaType{
-						Applicationjson: openapi.Applicationjson{
-							Schema: openapi.Schema{
-								Type:       "object",
-								Properties: properties,
-								Required:   required,
-							},
-						},
-					},
-					Required: true,
-				},
-				Responses: map[string]openapi.Response{
-					"200": {
-						Description: "Successful response",
-						Content: &openapi.MediaType{
-							Applicationjson: openapi.Applicationjson{
-								Schema: openapi.Schema{
-									Type: "object",
-									Properties: map[string]openapi.Property{
-										"ok": {
-											Type:        "boolean",
-											Description: "Request success indicator",
-										},
-										"result": g.convertMethodReturnType(m.ReturnType),
-									},
-									Required: []string{"ok", "result"},
-								},
-							},
-						},
-					},
-				},
-			},
+		if path := g.operations(m, properties, required, unionTypes); path.Get != nil || path.Post != nil {
+			openAPI.Paths["/"+m.Name] = path
 		}
-		openAPI.Paths["/"+m.Name] = pathItem
 	}
 
+	openAPI.Tags = g.tags()
+
 	g.log.Debug("OpenAPI generation complete")
 	return openAPI, nil
 }
 
-func (g *Generator) Save(openAPI *openapi.OpenAPI, outputPath string) error {
-	g.log.Debug("marshaling OpenAPI to JSON")
-	data, err := json.MarshalIndent(openAPI, "", "    ")
+// Save writes openAPI to outputPath in the format set by WithFormat. When
+// outputPath is a directory the file is named openapi-v<version>.json, or
+// .yaml for YAML. Nothing is written once ctx is done.
+func (g *Generator) Save(ctx context.Context, openAPI *openapi.OpenAPI, outputPath string) error {
+	format := g.format
+	if format == "" {
+		format = openapi.FormatJSON
+	}
+	g.log.Debug("marshaling OpenAPI", zap.String("format", format))
+	data, err := openapi.Marshal(openAPI, format)
 	if err != nil {
-		g.log.Error("error marshaling JSON", zap.Error(err))
-		return fmt.Errorf("error marshaling JSON: %w", err)
+		g.log.Error("error marshaling OpenAPI", zap.Error(err))
+		return fmt.Errorf("error marshaling OpenAPI: %w", err)
 	}
 
 	// Determine file path and directory
@@ -230,7 +277,7 @@ func (g *Generator) Save(openAPI *openapi.OpenAPI, outputPath string) error {
 		if path == "" {
 			path = "."
 		}
-		path += "/openapi-v%v.json"
+		path += "/openapi-v%v." + format
 	}
 
 	if strings.Contains(path, "%v") {
@@ -249,9 +296,11 @@ func (g *Generator) Save(openAPI *openapi.OpenAPI, outputPath string) error {
 		return fmt.Errorf("failed to create output directory: %w", err)
 	}
 
+	if err := ctx.Err(); err != nil {
+		return err
+	}
 	g.log.Debug("writing OpenAPI file", zap.String("path", path))
-	err = os.WriteFile(path, data, 0600)
-	if err != nil {
+	if err := fsutil.WriteFileAtomic(path, data); err != nil {
 		g.log.Error("error writing file", zap.Error(err), zap.String("path", path))
 		return fmt.Errorf("error write file: %w", err)
 	}
@@ -281,39 +330,92 @@ func (g *Generator) detectUnionTypes() map[string][]string {
 	return unions
 }
 
-func (g *Generator) convertDataTypeToProperty(dt telegram.DataType) openapi.Property {
+// unionDiscriminator returns the discriminator of a union, derived from the
+// fields of its variants: the first field that every variant fixes to a value
+// (e.g. "type" for BotCommandScope*, "status" for ChatMember*). The mapping
+// only lists values that select a single variant. Values shared by several
+// variants (InlineQueryResultPhoto and InlineQueryResultCachedPhoto are both
+// “photo”) are left out; the oneOf still tells those apart, since each
+// variant fixes the value with const and differs in its required fields.
+// It returns nil when no field is fixed in every variant.
+func (g *Generator) unionDiscriminator(name string, variants []string) *openapi.Discriminator {
+	if len(variants) == 0 {
+		return nil
+	}
+
+	consts := make([]map[string]string, len(variants))
+	for i, v := range variants {
+		t, ok := g.types[strings.ToLower(v)]
+		if !ok {
+			g.log.Debug("union variant not found; skipping discriminator", zap.String("union", name), zap.String("variant", v))
+			return nil
+		}
+		consts[i] = make(map[string]string)
+		for _, f := range t.Fields {
+			if f.Const != "" {
+				consts[i][f.Name] = f.Const
+			}
+		}
+	}
+
+	first := g.types[strings.ToLower(variants[0])]
+candidates:
+	for _, f := range first.Fields {
+		if f.Const == "" {
+			continue
+		}
+		owners := make(map[string][]string, len(variants))
+		for i, v := range variants {
+			value, ok := consts[i][f.Name]
+			if !ok {
+				continue candidates
+			}
+			owners[value] = append(owners[value], v)
+		}
+		d := &openapi.Discriminator{PropertyName: f.Name}
+		for value, vs := range owners {
+			if len(vs) > 1 {
+				g.log.Debug("union variants share a discriminator value; leaving it out of the mapping",
+					zap.String("union", name), zap.String("property", f.Name), zap.String("value", value), zap.Strings("variants", vs))
+				continue
+			}
+			if d.Mapping == nil {
+				d.Mapping = make(map[string]string, len(owners))
+			}
+			d.Mapping[value] = fmt.Sprintf("#/components/schemas/%s", vs[0])
+		}
+		return d
+	}
+	return nil
+}
+
+func (g *Generator) convertDataTypeToProperty(dt telegram.DataType) openapi.Schema {
 	if len(dt.Types) == 0 {
 		g.log.Warn("data type has no types; defaulting to object")
-		return openapi.Property{Type: "object"}
+		return openapi.Schema{Type: openapi.Types{"object"}}
 	}
 
 	if dt.IsArray {
-		var innerProperty openapi.Property
+		var innerProperty openapi.Schema
 		switch {
 		case len(dt.Types) > 1:
-			innerProperty = openapi.Property{
+			innerProperty = openapi.Schema{
 				OneOf: g.convertTypesToProperties(dt.Types),
 			}
-		case strings.HasPrefix(g.convertType(dt.Types[0]), "#/components/schemas/"):
-			innerProperty = openapi.Property{
-				Ref: g.convertType(dt.Types[0]),
-			}
 		default:
-			innerProperty = openapi.Property{
-				Type: g.convertType(dt.Types[0]),
-			}
+			innerProperty = g.convertTypeToProperty(dt.Types[0])
 		}
 
-		result := openapi.Property{
-			Type:  "array",
+		result := openapi.Schema{
+			Type:  openapi.Types{"array"},
 			Items: &innerProperty,
 		}
 
 		for i := 1; i < dt.ArrayDepth; i++ {
-			result = openapi.Property{
-				Type: "array",
-				Items: &openapi.Property{
-					Type:  "array",
+			result = openapi.Schema{
+				Type: openapi.Types{"array"},
+				Items: &openapi.Schema{
+					Type:  openapi.Types{"array"},
 					Items: result.Items,
 				},
 			}
@@ -322,37 +424,140 @@ func (g *Generator) convertDataTypeToProperty(dt telegram.DataType) openapi.Prop
 	}
 
 	if len(dt.Types) > 1 {
-		return openapi.Property{
+		return openapi.Schema{
 			OneOf: g.convertTypesToProperties(dt.Types),
 		}
 	}
 
-	if strings.HasPrefix(g.convertType(dt.Types[0]), "#/components/schemas/") {
-		return openapi.Property{
-			Ref: g.convertType(dt.Types[0]),
+	return g.convertTypeToProperty(dt.Types[0])
+}
+
+// applyEnum restricts a string property to values. For arrays the enum is
+// placed on the innermost items.
+func applyEnum(property *openapi.Schema, values []string) {
+	if len(values) == 0 {
+		return
+	}
+	for property.Items != nil {
+		property = property.Items
+	}
+	if !property.Type.Is("string") {
+		return
+	}
+	property.Enum = make([]any, len(values))
+	for i, v := range values {
+		property.Enum[i] = v
+	}
+}
+
+// applyConstraints copies documented limits onto a property. Item counts
+// apply to the array itself, everything else to its innermost items.
+func applyConstraints(property *openapi.Schema, c *telegram.Constraints) {
+	if c == nil {
+		return
+	}
+	property.MinItems, property.MaxItems = c.MinItems, c.MaxItems
+	for property.Items != nil {
+		property = property.Items
+	}
+	property.MinLength, property.MaxLength = c.MinLength, c.MaxLength
+	property.Minimum, property.Maximum = c.Minimum, c.Maximum
+}
+
+// applyIntegerFormat sets the format of integer leaves: int64 when the value
+// is documented (or inferred, see int64Names) as exceeding 32 bits or its
+// documented range doesn't fit 32 bits, int32 otherwise.
+func applyIntegerFormat(property *openapi.Schema, is64Bit bool) {
+	if property.Items != nil {
+		applyIntegerFormat(property.Items, is64Bit)
+	}
+	for i := range property.OneOf {
+		applyIntegerFormat(&property.OneOf[i], is64Bit)
+	}
+	if !property.Type.Is("integer") {
+		return
+	}
+	switch {
+	case is64Bit,
+		property.Minimum != nil && *property.Minimum < math.MinInt32,
+		property.Maximum != nil && *property.Maximum > math.MaxInt32:
+		property.Format = "int64"
+	default:
+		property.Format = "int32"
+	}
+}
+
+// int64Names returns the identifier names (ending in "_id") that carry 64-bit
+// integers wherever they appear. Parameters such as chat_id and user_id
+// usually don't repeat the 64-bit note of Chat.id and User.id, so a note on
+// one element with such a name, or on a type's "id" for "<type>_id", applies
+// to all of them. Other names only get int64 where the parser flagged them:
+// a "limit" or "offset" means different things in different places.
+func (g *Generator) int64Names() map[string]bool {
+	names := make(map[string]bool)
+	add := func(name string) {
+		if strings.HasSuffix(name, "_id") {
+			names[name] = true
 		}
 	}
-	return openapi.Property{
-		Type: g.convertType(dt.Types[0]),
+	for _, t := range g.types {
+		for _, f := range t.Fields {
+			if !f.Int64 {
+				continue
+			}
+			add(f.Name)
+			if f.Name == "id" {
+				add(toSnakeCase(t.Name) + "_id")
+			}
+		}
+	}
+	for _, m := range g.methods {
+		for _, p := range m.Parameters {
+			if p.Int64 {
+				add(p.Name)
+			}
+		}
 	}
+	return names
 }
 
-func (g *Generator) convertTypesToProperties(types []string) []openapi.Property {
-	properties := make([]openapi.Property, 0, len(types))
-	for _, t := range types {
-		if strings.HasPrefix(g.convertType(t), "#/components/schemas/") {
-			properties = append(properties, openapi.Property{
-				Ref: g.convertType(t),
-			})
-		} else {
-			properties = append(properties, openapi.Property{
-				Type: g.convertType(t),
-			})
+// toSnakeCase converts a type name such as ChatMember to chat_member.
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if unicode.IsUpper(r) {
+			if i > 0 {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
 		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+func (g *Generator) convertTypesToProperties(types []string) []openapi.Schema {
+	properties := make([]openapi.Schema, 0, len(types))
+	for _, t := range types {
+		properties = append(properties, g.convertTypeToProperty(t))
 	}
 	return properties
 }
 
+// convertTypeToProperty returns a $ref for object types and a typed leaf for
+// primitives. Floats are double precision.
+func (g *Generator) convertTypeToProperty(t string) openapi.Schema {
+	converted := g.convertType(t)
+	if strings.HasPrefix(converted, "#/components/schemas/") {
+		return openapi.Schema{Ref: converted}
+	}
+	property := openapi.Schema{Type: openapi.Types{converted}}
+	if converted == "number" {
+		property.Format = "double"
+	}
+	return property
+}
+
 func (g *Generator) convertType(t string) string {
 	switch t {
 	case "Integer", "Int":
@@ -396,28 +601,50 @@ func (g *Generator) convertStringSliceToDataType(types []string) telegram.DataTy
 	}
 }
 
-func (g *Generator) convertMethodReturnType(returnType telegram.ReturnType) openapi.Property {
-	if returnType.IsArray {
-		return g.convertDataTypeToProperty(telegram.DataType{
-			Types:      []string{returnType.Name},
-			IsArray:    true,
-			ArrayDepth: 1,
-		})
+// convertMethodReturnTypes converts the results of a method. Several
+// alternatives ("Message or True") become a oneOf; none means the
+// documentation doesn't say, which for the Bot API is True.
+func (g *Generator) convertMethodReturnTypes(returnTypes []telegram.ReturnType) openapi.Schema {
+	switch len(returnTypes) {
+	case 0:
+		return openapi.Schema{Type: openapi.Types{"boolean"}}
+	case 1:
+		return g.convertMethodReturnType(returnTypes[0])
+	}
+	property := openapi.Schema{OneOf: make([]openapi.Schema, 0, len(returnTypes))}
+	for _, rt := range returnTypes {
+		property.OneOf = append(property.OneOf, g.convertMethodReturnType(rt))
+	}
+	return property
+}
+
+// convertMethodReturnType converts a single method result.
+func (g *Generator) convertMethodReturnType(returnType telegram.ReturnType) openapi.Schema {
+
+	if returnType.IsArray || returnType.ArrayDepth > 0 {
+		inner := returnType
+		inner.IsArray, inner.ArrayDepth = false, 0
+		property := g.convertMethodReturnType(inner)
+		for i := 0; i < max(returnType.ArrayDepth, 1); i++ {
+			items := property
+			property = openapi.Schema{Type: openapi.Types{"array"}, Items: &items}
+		}
+		return property
 	}
 
 	switch returnType.Name {
 	case "":
-		return openapi.Property{Type: "boolean"}
+		return openapi.Schema{Type: openapi.Types{"boolean"}}
 	case "integer":
-		return openapi.Property{Type: "integer"}
+		return openapi.Schema{Type: openapi.Types{"integer"}, Format: "int32"}
 	case "boolean":
-		return openapi.Property{Type: "boolean"}
+		return openapi.Schema{Type: openapi.Types{"boolean"}}
 	case "String":
-		return openapi.Property{Type: "string"}
+		return openapi.Schema{Type: openapi.Types{"string"}}
 	case "True", "False":
-		return openapi.Property{Type: "boolean"}
+		return openapi.Schema{Type: openapi.Types{"boolean"}}
 	default:
-		return openapi.Property{
+		return openapi.Schema{
 			Ref: fmt.Sprintf("#/components/schemas/%s", returnType.Name),
 		}
 	}
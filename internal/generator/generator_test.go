@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"context"
 	"testing"
 
 	"github.com/superboomer/tg-spec-cli/internal/telegram"
@@ -22,7 +23,7 @@ func TestNewWithType(t *testing.T) {
 func TestGenerator_Generate(t *testing.T) {
 	log := zaptest.NewLogger(t)
 	gen := NewWithType(log, "1.0", map[string]telegram.Type{}, []telegram.Method{}, "gateway")
-	_, err := gen.Generate()
+	_, err := gen.Generate(context.Background())
 	if err != nil {
 		t.Logf("Generate() error: %v", err)
 	}
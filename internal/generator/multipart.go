@@ -0,0 +1,105 @@
+package generator
+
+import (
+	"strings"
+
+	"github.com/superboomer/tg-spec-cli/internal/openapi"
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+)
+
+const inputFileRef = "#/components/schemas/InputFile"
+
+// multipartBody returns the multipart/form-data body built from the JSON body
+// properties of a method. InputFile becomes a binary part and every parameter
+// that isn't a plain value or a file is sent as a JSON-serialized part. When
+// nested is set, files inside nested objects (InputMedia, InputSticker, …)
+// are referenced as attach://<name> and uploaded as extra parts.
+func multipartBody(properties map[string]openapi.Schema, required []string, nested bool) openapi.MediaType {
+	body := formBody(properties, required, func(p openapi.Schema) (openapi.Schema, bool) {
+		return binaryInputFile(p), true
+	})
+	if nested {
+		body.Schema.AdditionalProperties = &openapi.Schema{Type: openapi.Types{"string"}, Format: "binary"}
+	}
+	return body
+}
+
+// uploadsFiles reports whether a parameter of m is an InputFile (direct) or
+// can contain one through its fields or union variants (nested).
+func (g *Generator) uploadsFiles(m telegram.Method, unions map[string][]string) (direct, nested bool) {
+	for _, param := range m.Parameters {
+		for _, t := range param.Type.Types {
+			switch {
+			case t == "InputFile":
+				direct = true
+			case g.referencesInputFile(t, unions, map[string]bool{}):
+				nested = true
+			}
+		}
+	}
+	return direct, nested
+}
+
+// referencesInputFile reports whether the type name, one of its fields or
+// one of its union variants can hold an InputFile.
+func (g *Generator) referencesInputFile(name string, unions map[string][]string, visited map[string]bool) bool {
+	if name == "InputFile" {
+		return true
+	}
+	if visited[name] {
+		return false
+	}
+	visited[name] = true
+
+	for _, v := range unions[name] {
+		if g.referencesInputFile(v, unions, visited) {
+			return true
+		}
+	}
+	t, ok := g.types[strings.ToLower(name)]
+	if !ok {
+		return false
+	}
+	for _, f := range t.Fields {
+		for _, ft := range g.convertStringSliceToDataType(f.Type).Types {
+			if g.referencesInputFile(ft, unions, visited) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// binaryInputFile returns a copy of property with every InputFile reference
+// replaced by a binary string.
+func binaryInputFile(property openapi.Schema) openapi.Schema {
+	if property.Ref == inputFileRef {
+		return openapi.Schema{Type: openapi.Types{"string"}, Format: "binary", Description: property.Description}
+	}
+	if property.Items != nil {
+		items := binaryInputFile(*property.Items)
+		property.Items = &items
+	}
+	if property.OneOf != nil {
+		oneOf := make([]openapi.Schema, len(property.OneOf))
+		for i, p := range property.OneOf {
+			oneOf[i] = binaryInputFile(p)
+		}
+		property.OneOf = oneOf
+	}
+	return property
+}
+
+// isJSONPart reports whether a form part must be JSON-serialized, i.e. it is
+// (or may be) an object or an array rather than a scalar or a file.
+func isJSONPart(property openapi.Schema) bool {
+	if property.Ref != "" || property.Type.Is("array") || property.Type.Is("object") {
+		return true
+	}
+	for _, p := range property.OneOf {
+		if isJSONPart(p) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,130 @@
+package generator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/superboomer/tg-spec-cli/internal/openapi"
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+	"go.uber.org/zap"
+)
+
+// uploadTypes returns the InputMedia union with a variant whose thumbnail can
+// be uploaded, as used by sendMediaGroup and editMessageMedia.
+func uploadTypes() map[string]telegram.Type {
+	return map[string]telegram.Type{
+		"inputmedia": {
+			Name:        "InputMedia",
+			Description: "This object represents the content of a media message to be sent. It should be one of\n- InputMediaDocument\n",
+		},
+		"inputmediadocument": {
+			Name: "InputMediaDocument",
+			Fields: []telegram.Field{
+				{Name: "media", Type: []string{"String"}, Required: true},
+				{Name: "thumbnail", Type: []string{"InputFile", "String"}},
+			},
+		},
+		"replykeyboardremove": {
+			Name:   "ReplyKeyboardRemove",
+			Fields: []telegram.Field{{Name: "remove_keyboard", Type: []string{"True"}, Required: true}},
+		},
+	}
+}
+
+// uploadMethods returns a method with a direct file parameter, one uploading
+// through InputMediaDocument and one without files.
+func uploadMethods() []telegram.Method {
+	return []telegram.Method{
+		{
+			Name:        "sendDocument",
+			ReturnTypes: []telegram.ReturnType{{Name: "Message"}},
+			Parameters: []telegram.Parameter{
+				{Name: "chat_id", Type: telegram.DataType{Types: []string{"Integer", "String"}}, Required: true},
+				{Name: "document", Type: telegram.DataType{Types: []string{"InputFile", "String"}}, Required: true},
+				{Name: "reply_markup", Type: telegram.DataType{Types: []string{"ReplyKeyboardRemove"}}},
+			},
+		},
+		{
+			Name:        "sendMediaGroup",
+			ReturnTypes: []telegram.ReturnType{{Name: "Message", IsArray: true}},
+			Parameters: []telegram.Parameter{
+				{Name: "media", Type: telegram.DataType{Types: []string{"InputMediaDocument"}, IsArray: true, ArrayDepth: 1}, Required: true},
+			},
+		},
+		{
+			Name:        "sendMessage",
+			ReturnTypes: []telegram.ReturnType{{Name: "Message"}},
+			Parameters: []telegram.Parameter{
+				{Name: "text", Type: telegram.DataType{Types: []string{"String"}}, Required: true},
+			},
+		},
+	}
+}
+
+func TestGenerate_MultipartBodies(t *testing.T) {
+	spec, err := NewWithType(zap.NewNop(), "7.0", uploadTypes(), uploadMethods(), "botapi").Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	doc := spec.Paths["/sendDocument"].Post.RequestBody.Content
+	multipart, ok := doc[contentTypeMultipart]
+	if !ok {
+		t.Fatal("sendDocument should have a multipart/form-data body")
+	}
+	parts := multipart.Schema.Properties
+	if file := parts["document"].OneOf[0]; !file.Type.Is("string") || file.Format != "binary" {
+		t.Errorf("document file part = %+v, want binary string", file)
+	}
+	if ref := doc[contentTypeJSON].Schema.Properties["document"].OneOf[0].Ref; ref != inputFileRef {
+		t.Errorf("JSON body must keep the InputFile reference, got %q", ref)
+	}
+	if enc := multipart.Encoding; len(enc) != 1 || enc["reply_markup"].ContentType != "application/json" {
+		t.Errorf("encoding = %+v, want only reply_markup as application/json", enc)
+	}
+	if multipart.Schema.AdditionalProperties != nil {
+		t.Error("direct uploads need no attach:// parts")
+	}
+
+	group, ok := spec.Paths["/sendMediaGroup"].Post.RequestBody.Content[contentTypeMultipart]
+	if !ok {
+		t.Fatal("sendMediaGroup uploads through InputMediaDocument.thumbnail and should have a multipart body")
+	}
+	if group.Encoding["media"].ContentType != "application/json" {
+		t.Errorf("media should be a JSON part, got %+v", group.Encoding)
+	}
+	if extra := group.Schema.AdditionalProperties; extra == nil || extra.Format != "binary" {
+		t.Errorf("attach:// parts = %+v, want binary", extra)
+	}
+
+	if _, ok := spec.Paths["/sendMessage"].Post.RequestBody.Content[contentTypeMultipart]; ok {
+		t.Error("sendMessage takes no files and should not get a multipart body")
+	}
+}
+
+func TestReferencesInputFile(t *testing.T) {
+	g := NewWithType(zap.NewNop(), "7.0", uploadTypes(), nil, "botapi")
+	unions := g.detectUnionTypes()
+	for name, want := range map[string]bool{
+		"InputFile":           true,
+		"InputMedia":          true,
+		"InputMediaDocument":  true,
+		"ReplyKeyboardRemove": false,
+		"Unknown":             false,
+	} {
+		if got := g.referencesInputFile(name, unions, map[string]bool{}); got != want {
+			t.Errorf("referencesInputFile(%q) = %v, want %v", name, got, want)
+		}
+	}
+}
+
+func TestBinaryInputFile_DoesNotAlias(t *testing.T) {
+	original := openapi.Schema{Type: openapi.Types{"array"}, Items: &openapi.Schema{Ref: inputFileRef}}
+	converted := binaryInputFile(original)
+	if converted.Items.Format != "binary" {
+		t.Errorf("items = %+v, want binary", converted.Items)
+	}
+	if original.Items.Ref != inputFileRef {
+		t.Error("converting must not modify the JSON body's property")
+	}
+}
@@ -0,0 +1,51 @@
+package generator
+
+import (
+	"context"
+	"testing"
+
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+	"go.uber.org/zap"
+)
+
+func TestGenerate_Since(t *testing.T) {
+	types := map[string]telegram.Type{
+		"story": {Name: "Story", Since: "7.0", Fields: []telegram.Field{{Name: "id", Type: []string{"Integer"}, Required: true, Since: "7.0"}}},
+	}
+	methods := []telegram.Method{{
+		Name:        "sendMessage",
+		Since:       "7.2",
+		ReturnTypes: []telegram.ReturnType{{Name: "boolean"}},
+		Parameters: []telegram.Parameter{
+			{Name: "text", Type: telegram.DataType{Types: []string{"String"}}, Required: true},
+			{Name: "business_connection_id", Type: telegram.DataType{Types: []string{"String"}}, Since: "7.2"},
+		},
+	}}
+	spec, err := NewWithType(zap.NewNop(), "7.2", types, methods, "botapi").
+		WithOptions(Options{HTTPMethods: []string{"post", "get"}}).
+		Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	story := spec.Components.Schemas["Story"]
+	if story.XTelegramSince != "7.0" || story.Properties["id"].XTelegramSince != "7.0" {
+		t.Errorf("Story since = %q, id since = %q, want 7.0", story.XTelegramSince, story.Properties["id"].XTelegramSince)
+	}
+	path := spec.Paths["/sendMessage"]
+	if path.Post.XTelegramSince != "7.2" {
+		t.Errorf("operation since = %q, want 7.2", path.Post.XTelegramSince)
+	}
+	body := path.Post.RequestBody.Content[contentTypeJSON].Schema
+	if got := body.Properties["business_connection_id"].XTelegramSince; got != "7.2" {
+		t.Errorf("body property since = %q, want 7.2", got)
+	}
+	if got := body.Properties["text"].XTelegramSince; got != "" {
+		t.Errorf("text predates the changelog, got since %q", got)
+	}
+	for _, p := range path.Get.Parameters {
+		if p.Name == "business_connection_id" && p.XTelegramSince != "7.2" {
+			t.Errorf("query parameter since = %q, want 7.2", p.XTelegramSince)
+		}
+	}
+}
@@ -0,0 +1,33 @@
+package generator
+
+import "github.com/superboomer/tg-spec-cli/internal/openapi"
+
+// tags returns one tag per documentation section that contains methods, in
+// page order, described by the section's introduction. Sections of methods
+// that aren't in g.sections (e.g. a model without sections) are appended in
+// method order without a description.
+func (g *Generator) tags() []openapi.Tag {
+	used := make(map[string]bool)
+	var order []string
+	for _, m := range g.methods {
+		if m.Section != "" && !used[m.Section] {
+			used[m.Section] = true
+			order = append(order, m.Section)
+		}
+	}
+
+	var tags []openapi.Tag
+	listed := make(map[string]bool)
+	for _, s := range g.sections {
+		if used[s.Name] && !listed[s.Name] {
+			listed[s.Name] = true
+			tags = append(tags, openapi.Tag{Name: s.Name, Description: s.Description})
+		}
+	}
+	for _, name := range order {
+		if !listed[name] {
+			tags = append(tags, openapi.Tag{Name: name})
+		}
+	}
+	return tags
+}
@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"context"
+	"reflect"
+	"testing"
+
+	"github.com/superboomer/tg-spec-cli/internal/openapi"
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+	"go.uber.org/zap"
+)
+
+func TestGenerate_Tags(t *testing.T) {
+	methods := []telegram.Method{
+		{Name: "getMe", Section: "Available methods", ReturnTypes: []telegram.ReturnType{{Name: "boolean"}}},
+		{Name: "sendSticker", Section: "Stickers", ReturnTypes: []telegram.ReturnType{{Name: "boolean"}}},
+		{Name: "sendGame", Section: "Games", ReturnTypes: []telegram.ReturnType{{Name: "boolean"}}},
+		{Name: "untagged", ReturnTypes: []telegram.ReturnType{{Name: "boolean"}}},
+	}
+	sections := []telegram.Section{
+		{Name: "Recent changes", Description: "Subscribe to @BotNews."},
+		{Name: "Stickers", Description: "Methods for stickers."},
+		{Name: "Available methods", Description: "All methods are case-insensitive."},
+	}
+	spec, err := NewWithType(zap.NewNop(), "7.0", map[string]telegram.Type{}, methods, "botapi").
+		WithSections(sections).
+		Generate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []openapi.Tag{
+		{Name: "Stickers", Description: "Methods for stickers."},
+		{Name: "Available methods", Description: "All methods are case-insensitive."},
+		{Name: "Games"},
+	}
+	if !reflect.DeepEqual(spec.Tags, want) {
+		t.Errorf("tags = %+v, want %+v", spec.Tags, want)
+	}
+	if tags := spec.Paths["/sendSticker"].Post.Tags; !reflect.DeepEqual(tags, []string{"Stickers"}) {
+		t.Errorf("sendSticker tags = %v, want [Stickers]", tags)
+	}
+	if tags := spec.Paths["/untagged"].Post.Tags; tags != nil {
+		t.Errorf("a method without a section must have no tags, got %v", tags)
+	}
+}
@@ -0,0 +1,215 @@
+// Package model defines the intermediate representation (IR) of a parsed
+// Telegram API documentation page.
+//
+// The IR is what sits between scraping and OpenAPI generation: the types and
+// methods exactly as the parser understood them. It is serialized as JSON so
+// other tools can consume it and so a spec can be regenerated later without
+// scraping the documentation again.
+//
+// The format is versioned by SchemaVersion, which is bumped whenever a change
+// would break existing readers. Adding optional fields does not bump it.
+package model
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+)
+
+// SchemaVersion is the version of the IR format written by this package.
+const SchemaVersion = 2
+
+// Model is the root of the IR document.
+type Model struct {
+	// SchemaVersion is the IR format version, see SchemaVersion.
+	SchemaVersion int `json:"schema_version"`
+	// APIType is the kind of documentation the model was parsed from:
+	// "botapi" or "gateway".
+	APIType string `json:"api_type"`
+	// APIVersion is the documented API version, e.g. "9.1".
+	APIVersion string `json:"api_version"`
+	// Types are the object types, sorted by name.
+	Types []telegram.Type `json:"types"`
+	// Methods are the API methods in documentation order.
+	Methods []telegram.Method `json:"methods"`
+	// DocsURL is the public documentation page of the API; anchors of types
+	// and methods are relative to it.
+	DocsURL string `json:"docs_url,omitempty"`
+	// Sections are the documentation's <h3> sections in page order; types
+	// and methods refer to them by name.
+	Sections []telegram.Section `json:"sections,omitempty"`
+	// Releases are the API releases listed under "Recent changes", newest
+	// first.
+	Releases []telegram.Release `json:"releases,omitempty"`
+	// Report lists parser findings worth a human look, such as constraint
+	// phrases that couldn't be interpreted. It is informational only.
+	Report *telegram.Report `json:"report,omitempty"`
+}
+
+// New builds a model from parser output.
+func New(apiType, apiVersion string, types map[string]telegram.Type, methods []telegram.Method) *Model {
+	m := &Model{
+		SchemaVersion: SchemaVersion,
+		APIType:       apiType,
+		APIVersion:    apiVersion,
+		Types:         make([]telegram.Type, 0, len(types)),
+		Methods:       methods,
+	}
+	for _, t := range types {
+		m.Types = append(m.Types, t)
+	}
+	sort.Slice(m.Types, func(i, j int) bool { return m.Types[i].Name < m.Types[j].Name })
+	if m.Methods == nil {
+		m.Methods = []telegram.Method{}
+	}
+	return m
+}
+
+// FromPage parses the types, methods, sections and releases of a
+// documentation page of the given API type into a model. The version of each
+// element is filled in from the releases (see telegram.ApplySince), and
+// DocsURL is the page's BaseURL. It fails with telegram.ErrLayoutChanged when
+// the page has neither types nor methods.
+func FromPage(ctx context.Context, apiType string, page *telegram.PageAPI) (*Model, error) {
+	version, err := page.GetVersion()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get version: %w", err)
+	}
+	types, err := page.GetTypes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get types: %w", err)
+	}
+	methods, err := page.GetMethods(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get methods: %w", err)
+	}
+	if len(types) == 0 && len(methods) == 0 {
+		return nil, fmt.Errorf("%w: no types or methods found", telegram.ErrLayoutChanged)
+	}
+	// The Gateway API page has no changelog, so a missing one isn't an
+	// error.
+	releases, _ := page.GetReleases()
+	telegram.ApplySince(types, methods, releases)
+
+	m := New(apiType, version, types, methods)
+	m.Sections = page.GetSections()
+	m.DocsURL = page.BaseURL
+	m.Releases = releases
+	if len(page.Report.UnparsedConstraints) > 0 {
+		m.Report = &page.Report
+	}
+	return m, nil
+}
+
+// TypeMap returns the types keyed by lower-cased name, the same layout the
+// parser produces.
+func (m *Model) TypeMap() map[string]telegram.Type {
+	types := make(map[string]telegram.Type, len(m.Types))
+	for _, t := range m.Types {
+		types[strings.ToLower(t.Name)] = t
+	}
+	return types
+}
+
+// Write serializes the model as indented JSON.
+func (m *Model) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "    ")
+	if err := enc.Encode(m); err != nil {
+		return fmt.Errorf("failed to encode model: %w", err)
+	}
+	return nil
+}
+
+// Read decodes a model and checks that its format version is supported.
+func Read(r io.Reader) (*Model, error) {
+	var m Model
+	if err := json.NewDecoder(r).Decode(&m); err != nil {
+		return nil, fmt.Errorf("failed to decode model: %w", err)
+	}
+	if m.SchemaVersion != SchemaVersion {
+		return nil, fmt.Errorf("unsupported model schema version %d (expected %d)", m.SchemaVersion, SchemaVersion)
+	}
+	return &m, nil
+}
+
+// AsOf returns a copy of the model as it stood at API version version: the
+// types, methods, fields and parameters introduced by later releases are
+// dropped, as are the later releases themselves, and union types lose the
+// variants that didn't exist yet. Elements without a Since predate the
+// listed releases and are always kept.
+func (m *Model) AsOf(version string) *Model {
+	after := func(since string) bool {
+		return since != "" && telegram.CompareVersions(since, version) > 0
+	}
+
+	out := *m
+	out.APIVersion = version
+
+	dropped := make(map[string]bool)
+	for _, t := range m.Types {
+		if after(t.Since) {
+			dropped[t.Name] = true
+		}
+	}
+	out.Types = make([]telegram.Type, 0, len(m.Types))
+	for _, t := range m.Types {
+		if dropped[t.Name] {
+			continue
+		}
+		fields := t.Fields
+		t.Fields = nil
+		for _, f := range fields {
+			if !after(f.Since) {
+				t.Fields = append(t.Fields, f)
+			}
+		}
+		t.Description = withoutVariants(t.Description, dropped)
+		out.Types = append(out.Types, t)
+	}
+
+	out.Methods = make([]telegram.Method, 0, len(m.Methods))
+	for _, method := range m.Methods {
+		if after(method.Since) {
+			continue
+		}
+		params := method.Parameters
+		method.Parameters = nil
+		for _, p := range params {
+			if !after(p.Since) {
+				method.Parameters = append(method.Parameters, p)
+			}
+		}
+		out.Methods = append(out.Methods, method)
+	}
+
+	out.Releases = nil
+	for _, r := range m.Releases {
+		if !after(r.Version) {
+			out.Releases = append(out.Releases, r)
+		}
+	}
+	return &out
+}
+
+// withoutVariants removes the "- Variant" lines of a union description that
+// name dropped types.
+func withoutVariants(description string, dropped map[string]bool) string {
+	if len(dropped) == 0 || !telegram.IsUnionDescription(description) {
+		return description
+	}
+	lines := strings.Split(description, "\n")
+	kept := lines[:0]
+	for _, line := range lines {
+		if variant, ok := strings.CutPrefix(strings.TrimSpace(line), "-"); ok && dropped[strings.TrimSpace(variant)] {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	return strings.Join(kept, "\n")
+}
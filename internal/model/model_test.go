@@ -0,0 +1,116 @@
+package model
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/superboomer/tg-spec-cli/internal/telegram"
+)
+
+func TestModel_RoundTrip(t *testing.T) {
+	types := map[string]telegram.Type{
+		"user": {Name: "User", Fields: []telegram.Field{{Name: "id", Type: []string{"Integer"}, Required: true}}},
+		"chat": {Name: "Chat"},
+	}
+	methods := []telegram.Method{{
+		Name:        "getUpdates",
+		ReturnTypes: []telegram.ReturnType{{Name: "Update", IsArray: true}},
+		Parameters:  []telegram.Parameter{{Name: "limit", Type: telegram.DataType{Types: []string{"Integer"}}}},
+	}}
+	m := New("botapi", "7.0", types, methods)
+	if m.Types[0].Name != "Chat" || m.Types[1].Name != "User" {
+		t.Errorf("types should be sorted by name, got %v", m.Types)
+	}
+
+	var buf bytes.Buffer
+	if err := m.Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"schema_version": 2`) {
+		t.Errorf("model should record its schema version:\n%s", buf.String())
+	}
+
+	got, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("Read() error = %v", err)
+	}
+	if got.APIVersion != "7.0" || got.APIType != "botapi" {
+		t.Errorf("unexpected header: %+v", got)
+	}
+	if _, ok := got.TypeMap()["user"]; !ok {
+		t.Error("TypeMap() should key types by lower-cased name")
+	}
+	if rts := got.Methods[0].ReturnTypes; len(rts) != 1 || rts[0].Name != "Update" || !rts[0].IsArray {
+		t.Errorf("return types lost in round trip: %+v", rts)
+	}
+}
+
+func TestNew_NilMethods(t *testing.T) {
+	var buf bytes.Buffer
+	if err := New("botapi", "7.0", nil, nil).Write(&buf); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(buf.String(), `"methods": []`) {
+		t.Errorf("empty methods should serialize as [], got:\n%s", buf.String())
+	}
+}
+
+func TestRead_Errors(t *testing.T) {
+	if _, err := Read(strings.NewReader("{not json")); err == nil {
+		t.Error("Read() should reject invalid JSON")
+	}
+	if _, err := Read(strings.NewReader(`{"schema_version": 99}`)); err == nil {
+		t.Error("Read() should reject unknown schema versions")
+	}
+}
+
+func TestModel_AsOf(t *testing.T) {
+	m := &Model{
+		APIVersion: "7.10",
+		Types: []telegram.Type{
+			{Name: "ChatBoostSource", Description: "It can be one of\n- ChatBoostSourcePremium\n- ChatBoostSourceGiveaway\n"},
+			{Name: "ChatBoostSourceGiveaway", Since: "7.10"},
+			{Name: "ChatBoostSourcePremium", Since: "7.0"},
+			{Name: "Message", Fields: []telegram.Field{{Name: "message_id"}, {Name: "paid_media", Since: "7.6"}, {Name: "story", Since: "7.0"}}},
+		},
+		Methods: []telegram.Method{
+			{Name: "sendMessage", Parameters: []telegram.Parameter{{Name: "text"}, {Name: "message_effect_id", Since: "7.4"}}},
+			{Name: "sendPaidMedia", Since: "7.6"},
+		},
+		Releases: []telegram.Release{{Version: "7.10"}, {Version: "7.6"}, {Version: "7.4"}, {Version: "7.0"}},
+	}
+
+	got := m.AsOf("7.4")
+	if got.APIVersion != "7.4" {
+		t.Errorf("APIVersion = %q, want 7.4", got.APIVersion)
+	}
+	var types []string
+	for _, ty := range got.Types {
+		types = append(types, ty.Name)
+	}
+	if want := []string{"ChatBoostSource", "ChatBoostSourcePremium", "Message"}; !reflect.DeepEqual(types, want) {
+		t.Errorf("types = %v, want %v", types, want)
+	}
+	if desc := got.Types[0].Description; desc != "It can be one of\n- ChatBoostSourcePremium\n" {
+		t.Errorf("union description = %q, want the dropped variant removed", desc)
+	}
+	if fields := got.Types[2].Fields; len(fields) != 2 || fields[1].Name != "story" {
+		t.Errorf("Message fields = %+v, want message_id and story", fields)
+	}
+	if len(got.Methods) != 1 || len(got.Methods[0].Parameters) != 2 {
+		t.Errorf("methods = %+v, want sendMessage with both parameters", got.Methods)
+	}
+	if len(got.Releases) != 2 || got.Releases[0].Version != "7.4" {
+		t.Errorf("releases = %+v, want 7.4 and 7.0", got.Releases)
+	}
+
+	// "7.10" sorts after "7.9", so nothing is dropped.
+	if got := m.AsOf("7.10"); len(got.Types) != 4 || len(got.Methods) != 2 || len(got.Types[3].Fields) != 3 {
+		t.Errorf("AsOf(7.10) dropped elements: %+v", got)
+	}
+	if len(m.Types) != 4 || len(m.Types[3].Fields) != 3 {
+		t.Error("AsOf must not modify the original model")
+	}
+}
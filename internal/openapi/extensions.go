@@ -0,0 +1,491 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Extensions are the specification extensions of an object: its "x-" keys
+// that have no dedicated field, such as "x-codegen-request-body-name". They
+// are kept when a document is loaded and written back unchanged.
+type Extensions map[string]any
+
+// marshalExtended encodes v, the plain version of an object, and appends
+// its extensions in key order. Fields tagged omitempty are left out when
+// they are nil, false, zero or empty strings, and structs when they are
+// zero; empty maps and slices are kept, so {"paths": {}} or {"required": []}
+// are written back as loaded.
+func marshalExtended(v any, ext Extensions) ([]byte, error) {
+	rv := reflect.ValueOf(v)
+	rt := rv.Type()
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	write := func(key string, value any) error {
+		data, err := json.Marshal(value)
+		if err != nil {
+			return err
+		}
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		name, _ := json.Marshal(key)
+		buf.Write(name)
+		buf.WriteByte(':')
+		buf.Write(data)
+		return nil
+	}
+
+	for i := 0; i < rt.NumField(); i++ {
+		f := rt.Field(i)
+		name, opts, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		fv := rv.Field(i)
+		if opts == "omitempty" && omitted(fv) {
+			continue
+		}
+		if err := write(name, fv.Interface()); err != nil {
+			return nil, err
+		}
+	}
+
+	known := fieldNames(rt)
+	keys := make([]string, 0, len(ext))
+	for k := range ext {
+		if !known[k] {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if err := write(k, ext[k]); err != nil {
+			return nil, fmt.Errorf("failed to encode extension %s: %w", k, err)
+		}
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// omitted reports whether an omitempty field with value v is left out.
+func omitted(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Map, reflect.Slice, reflect.Pointer, reflect.Interface:
+		return v.IsNil()
+	default:
+		return v.IsZero()
+	}
+}
+
+// unmarshalExtended decodes data into v, a pointer to the plain version of
+// an object, and returns the "x-" keys that have no field of their own.
+// Numbers in extensions keep their exact text.
+func unmarshalExtended(data []byte, v any) (Extensions, error) {
+	return unmarshalUnknown(data, v, func(key string) bool { return strings.HasPrefix(key, "x-") })
+}
+
+// unmarshalUnknown decodes data into v and returns the keys without a field
+// for which keep is true.
+func unmarshalUnknown(data []byte, v any, keep func(key string) bool) (Extensions, error) {
+	if err := json.Unmarshal(data, v); err != nil {
+		return nil, err
+	}
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	known := fieldNames(reflect.TypeOf(v).Elem())
+	var ext Extensions
+	for k, r := range raw {
+		if !keep(k) || known[k] {
+			continue
+		}
+		value, err := decodeValue(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode extension %s: %w", k, err)
+		}
+		if ext == nil {
+			ext = Extensions{}
+		}
+		ext[k] = value
+	}
+	return ext, nil
+}
+
+// decodeValue decodes a JSON value, keeping the exact text of numbers.
+func decodeValue(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+var fieldNamesCache sync.Map
+
+// fieldNames returns the JSON keys of the fields of struct type t.
+func fieldNames(t reflect.Type) map[string]bool {
+	if names, ok := fieldNamesCache.Load(t); ok {
+		return names.(map[string]bool)
+	}
+	names := make(map[string]bool)
+	for i := 0; i < t.NumField(); i++ {
+		name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ",")
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	fieldNamesCache.Store(t, names)
+	return names
+}
+
+func (o OpenAPI) MarshalJSON() ([]byte, error) {
+	type plain OpenAPI
+	return marshalExtended(plain(o), o.Extensions)
+}
+
+func (o *OpenAPI) UnmarshalJSON(data []byte) error {
+	type plain OpenAPI
+	ext, err := unmarshalExtended(data, (*plain)(o))
+	o.Extensions = ext
+	return err
+}
+
+func (t Tag) MarshalJSON() ([]byte, error) {
+	type plain Tag
+	return marshalExtended(plain(t), t.Extensions)
+}
+
+func (t *Tag) UnmarshalJSON(data []byte) error {
+	type plain Tag
+	ext, err := unmarshalExtended(data, (*plain)(t))
+	t.Extensions = ext
+	return err
+}
+
+func (i Info) MarshalJSON() ([]byte, error) {
+	type plain Info
+	return marshalExtended(plain(i), i.Extensions)
+}
+
+func (i *Info) UnmarshalJSON(data []byte) error {
+	type plain Info
+	ext, err := unmarshalExtended(data, (*plain)(i))
+	i.Extensions = ext
+	return err
+}
+
+func (c Contact) MarshalJSON() ([]byte, error) {
+	type plain Contact
+	return marshalExtended(plain(c), c.Extensions)
+}
+
+func (c *Contact) UnmarshalJSON(data []byte) error {
+	type plain Contact
+	ext, err := unmarshalExtended(data, (*plain)(c))
+	c.Extensions = ext
+	return err
+}
+
+func (l License) MarshalJSON() ([]byte, error) {
+	type plain License
+	return marshalExtended(plain(l), l.Extensions)
+}
+
+func (l *License) UnmarshalJSON(data []byte) error {
+	type plain License
+	ext, err := unmarshalExtended(data, (*plain)(l))
+	l.Extensions = ext
+	return err
+}
+
+func (s Server) MarshalJSON() ([]byte, error) {
+	type plain Server
+	return marshalExtended(plain(s), s.Extensions)
+}
+
+func (s *Server) UnmarshalJSON(data []byte) error {
+	type plain Server
+	ext, err := unmarshalExtended(data, (*plain)(s))
+	s.Extensions = ext
+	return err
+}
+
+func (v ServerVariable) MarshalJSON() ([]byte, error) {
+	type plain ServerVariable
+	return marshalExtended(plain(v), v.Extensions)
+}
+
+func (v *ServerVariable) UnmarshalJSON(data []byte) error {
+	type plain ServerVariable
+	ext, err := unmarshalExtended(data, (*plain)(v))
+	v.Extensions = ext
+	return err
+}
+
+func (p Path) MarshalJSON() ([]byte, error) {
+	type plain Path
+	return marshalExtended(plain(p), p.Extensions)
+}
+
+func (p *Path) UnmarshalJSON(data []byte) error {
+	type plain Path
+	ext, err := unmarshalExtended(data, (*plain)(p))
+	p.Extensions = ext
+	return err
+}
+
+func (o Operation) MarshalJSON() ([]byte, error) {
+	type plain Operation
+	return marshalExtended(plain(o), o.Extensions)
+}
+
+func (o *Operation) UnmarshalJSON(data []byte) error {
+	type plain Operation
+	ext, err := unmarshalExtended(data, (*plain)(o))
+	o.Extensions = ext
+	return err
+}
+
+func (e ExternalDocs) MarshalJSON() ([]byte, error) {
+	type plain ExternalDocs
+	return marshalExtended(plain(e), e.Extensions)
+}
+
+func (e *ExternalDocs) UnmarshalJSON(data []byte) error {
+	type plain ExternalDocs
+	ext, err := unmarshalExtended(data, (*plain)(e))
+	e.Extensions = ext
+	return err
+}
+
+func (p Parameter) MarshalJSON() ([]byte, error) {
+	type plain Parameter
+	return marshalExtended(plain(p), p.Extensions)
+}
+
+func (p *Parameter) UnmarshalJSON(data []byte) error {
+	type plain Parameter
+	ext, err := unmarshalExtended(data, (*plain)(p))
+	p.Extensions = ext
+	return err
+}
+
+func (r RequestBody) MarshalJSON() ([]byte, error) {
+	type plain RequestBody
+	return marshalExtended(plain(r), r.Extensions)
+}
+
+func (r *RequestBody) UnmarshalJSON(data []byte) error {
+	type plain RequestBody
+	ext, err := unmarshalExtended(data, (*plain)(r))
+	r.Extensions = ext
+	return err
+}
+
+func (m MediaType) MarshalJSON() ([]byte, error) {
+	type plain MediaType
+	return marshalExtended(plain(m), m.Extensions)
+}
+
+func (m *MediaType) UnmarshalJSON(data []byte) error {
+	type plain MediaType
+	ext, err := unmarshalExtended(data, (*plain)(m))
+	m.Extensions = ext
+	return err
+}
+
+func (e Encoding) MarshalJSON() ([]byte, error) {
+	type plain Encoding
+	return marshalExtended(plain(e), e.Extensions)
+}
+
+func (e *Encoding) UnmarshalJSON(data []byte) error {
+	type plain Encoding
+	ext, err := unmarshalExtended(data, (*plain)(e))
+	e.Extensions = ext
+	return err
+}
+
+func (h Header) MarshalJSON() ([]byte, error) {
+	type plain Header
+	return marshalExtended(plain(h), h.Extensions)
+}
+
+func (h *Header) UnmarshalJSON(data []byte) error {
+	type plain Header
+	ext, err := unmarshalExtended(data, (*plain)(h))
+	h.Extensions = ext
+	return err
+}
+
+func (e Example) MarshalJSON() ([]byte, error) {
+	type plain Example
+	return marshalExtended(plain(e), e.Extensions)
+}
+
+func (e *Example) UnmarshalJSON(data []byte) error {
+	type plain Example
+	ext, err := unmarshalExtended(data, (*plain)(e))
+	e.Extensions = ext
+	return err
+}
+
+func (l Link) MarshalJSON() ([]byte, error) {
+	type plain Link
+	return marshalExtended(plain(l), l.Extensions)
+}
+
+func (l *Link) UnmarshalJSON(data []byte) error {
+	type plain Link
+	ext, err := unmarshalExtended(data, (*plain)(l))
+	l.Extensions = ext
+	return err
+}
+
+func (x XML) MarshalJSON() ([]byte, error) {
+	type plain XML
+	return marshalExtended(plain(x), x.Extensions)
+}
+
+func (x *XML) UnmarshalJSON(data []byte) error {
+	type plain XML
+	ext, err := unmarshalExtended(data, (*plain)(x))
+	x.Extensions = ext
+	return err
+}
+
+func (d Discriminator) MarshalJSON() ([]byte, error) {
+	type plain Discriminator
+	return marshalExtended(plain(d), d.Extensions)
+}
+
+func (d *Discriminator) UnmarshalJSON(data []byte) error {
+	type plain Discriminator
+	ext, err := unmarshalExtended(data, (*plain)(d))
+	d.Extensions = ext
+	return err
+}
+
+func (r Response) MarshalJSON() ([]byte, error) {
+	type plain Response
+	return marshalExtended(plain(r), r.Extensions)
+}
+
+func (r *Response) UnmarshalJSON(data []byte) error {
+	type plain Response
+	ext, err := unmarshalExtended(data, (*plain)(r))
+	r.Extensions = ext
+	return err
+}
+
+func (c Components) MarshalJSON() ([]byte, error) {
+	type plain Components
+	return marshalExtended(plain(c), c.Extensions)
+}
+
+func (c *Components) UnmarshalJSON(data []byte) error {
+	type plain Components
+	ext, err := unmarshalExtended(data, (*plain)(c))
+	c.Extensions = ext
+	return err
+}
+
+func (s SecurityScheme) MarshalJSON() ([]byte, error) {
+	type plain SecurityScheme
+	return marshalExtended(plain(s), s.Extensions)
+}
+
+func (s *SecurityScheme) UnmarshalJSON(data []byte) error {
+	type plain SecurityScheme
+	ext, err := unmarshalExtended(data, (*plain)(s))
+	s.Extensions = ext
+	return err
+}
+
+func (f OAuthFlows) MarshalJSON() ([]byte, error) {
+	type plain OAuthFlows
+	return marshalExtended(plain(f), f.Extensions)
+}
+
+func (f *OAuthFlows) UnmarshalJSON(data []byte) error {
+	type plain OAuthFlows
+	ext, err := unmarshalExtended(data, (*plain)(f))
+	f.Extensions = ext
+	return err
+}
+
+func (f OAuthFlow) MarshalJSON() ([]byte, error) {
+	type plain OAuthFlow
+	return marshalExtended(plain(f), f.Extensions)
+}
+
+func (f *OAuthFlow) UnmarshalJSON(data []byte) error {
+	type plain OAuthFlow
+	ext, err := unmarshalExtended(data, (*plain)(f))
+	f.Extensions = ext
+	return err
+}
+
+// A callback is written as a map of expressions to path items, so its
+// extensions sit among the expressions.
+func (c Callback) MarshalJSON() ([]byte, error) {
+	if c.Ref != "" {
+		return marshalExtended(struct {
+			Ref string `json:"$ref"`
+		}{c.Ref}, c.Extensions)
+	}
+	entries := make(map[string]any, len(c.Paths)+len(c.Extensions))
+	for k, v := range c.Extensions {
+		entries[k] = v
+	}
+	for k, v := range c.Paths {
+		entries[k] = v
+	}
+	return json.Marshal(entries)
+}
+
+func (c *Callback) UnmarshalJSON(data []byte) error {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	*c = Callback{}
+	for k, r := range raw {
+		switch {
+		case k == "$ref":
+			if err := json.Unmarshal(r, &c.Ref); err != nil {
+				return err
+			}
+		case strings.HasPrefix(k, "x-"):
+			value, err := decodeValue(r)
+			if err != nil {
+				return fmt.Errorf("failed to decode extension %s: %w", k, err)
+			}
+			if c.Extensions == nil {
+				c.Extensions = Extensions{}
+			}
+			c.Extensions[k] = value
+		default:
+			var p Path
+			if err := json.Unmarshal(r, &p); err != nil {
+				return err
+			}
+			if c.Paths == nil {
+				c.Paths = map[string]Path{}
+			}
+			c.Paths[k] = p
+		}
+	}
+	return nil
+}
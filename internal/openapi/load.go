@@ -0,0 +1,102 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Load reads an OpenAPI document in JSON or YAML. Documents starting with
+// "{" are read as JSON, everything else as YAML. Unknown "x-" keys are kept
+// in the Extensions of the objects they belong to.
+func Load(r io.Reader) (*OpenAPI, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read document: %w", err)
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) == 0 || trimmed[0] != '{' {
+		if data, err = yamlToJSON(data); err != nil {
+			return nil, err
+		}
+	}
+
+	var doc OpenAPI
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("failed to decode document: %w", err)
+	}
+	if doc.OpenAPI == "" {
+		return nil, errors.New("not an OpenAPI document: missing the openapi version")
+	}
+	return &doc, nil
+}
+
+// jsonNumberRe matches YAML numbers that are valid JSON numbers as written.
+var jsonNumberRe = regexp.MustCompile(`^-?(?:0|[1-9]\d*)(?:\.\d+)?(?:[eE][+-]?\d+)?$`)
+
+// yamlToJSON converts a YAML document to JSON. Mapping keys are always
+// strings, so unquoted response codes such as 200 stay valid.
+func yamlToJSON(data []byte) ([]byte, error) {
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil, fmt.Errorf("failed to decode YAML: %w", err)
+	}
+	value, err := yamlValue(&root)
+	if err != nil {
+		return nil, err
+	}
+	out, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert YAML: %w", err)
+	}
+	return out, nil
+}
+
+func yamlValue(n *yaml.Node) (any, error) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return yamlValue(n.Content[0])
+	case yaml.AliasNode:
+		return yamlValue(n.Alias)
+	case yaml.MappingNode:
+		m := make(map[string]any, len(n.Content)/2)
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			v, err := yamlValue(n.Content[i+1])
+			if err != nil {
+				return nil, err
+			}
+			m[n.Content[i].Value] = v
+		}
+		return m, nil
+	case yaml.SequenceNode:
+		s := make([]any, 0, len(n.Content))
+		for _, c := range n.Content {
+			v, err := yamlValue(c)
+			if err != nil {
+				return nil, err
+			}
+			s = append(s, v)
+		}
+		return s, nil
+	default:
+		switch n.ShortTag() {
+		case "!!int", "!!float":
+			// Keep the literal so large integers don't lose precision.
+			if jsonNumberRe.MatchString(n.Value) {
+				return json.Number(n.Value), nil
+			}
+		}
+		var v any
+		if err := n.Decode(&v); err != nil {
+			return nil, fmt.Errorf("line %d: %w", n.Line, err)
+		}
+		return v, nil
+	}
+}
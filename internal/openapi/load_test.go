@@ -0,0 +1,278 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const loadJSON = `{
+    "openapi": "3.1.0",
+    "info": {"title": "Test", "version": "1.0", "x-logo": {"url": "https://example.com/logo.png"}},
+    "servers": [{"url": "https://example.com", "x-internal": true}],
+    "paths": {
+        "/pets": {
+            "put": {
+                "summary": "Replace pets",
+                "operationId": "putPets",
+                "x-codegen-request-body-name": "body",
+                "x-telegram-anchor": "putpets",
+                "requestBody": {
+                    "required": true,
+                    "content": {"application/vnd.pets+json": {"schema": {"$ref": "#/components/schemas/Pet"}}}
+                },
+                "responses": {"200": {"$ref": "#/components/responses/Ok"}}
+            }
+        }
+    },
+    "components": {
+        "schemas": {
+            "Pet": {"type": "object", "x-order": 12345678901234567890, "properties": {"id": {"type": "integer", "x-go-type": "int64"}}}
+        },
+        "responses": {"Ok": {"description": "OK"}}
+    },
+    "x-tag-groups": [{"name": "Pets", "tags": ["pets"]}]
+}`
+
+const loadYAML = `
+openapi: 3.1.0
+info:
+  title: Test
+  version: "1.0"
+  x-logo:
+    url: https://example.com/logo.png
+servers:
+  - url: https://example.com
+    x-internal: true
+paths:
+  /pets:
+    put:
+      summary: Replace pets
+      operationId: putPets
+      x-codegen-request-body-name: body
+      x-telegram-anchor: putpets
+      requestBody:
+        required: true
+        content:
+          application/vnd.pets+json:
+            schema:
+              $ref: '#/components/schemas/Pet'
+      responses:
+        200:
+          $ref: '#/components/responses/Ok'
+components:
+  schemas:
+    Pet:
+      type: object
+      x-order: 12345678901234567890
+      properties:
+        id:
+          type: integer
+          x-go-type: int64
+  responses:
+    Ok:
+      description: OK
+x-tag-groups:
+  - name: Pets
+    tags: [pets]
+`
+
+func TestLoad(t *testing.T) {
+	for name, input := range map[string]string{"json": loadJSON, "yaml": loadYAML} {
+		t.Run(name, func(t *testing.T) {
+			doc, err := Load(strings.NewReader(input))
+			if err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+			op := doc.Paths["/pets"].Put
+			if op == nil || op.OperationID != "putPets" {
+				t.Fatalf("expected the PUT operation, got %+v", doc.Paths["/pets"])
+			}
+			if op.XTelegramAnchor != "putpets" {
+				t.Errorf("typed extension = %q, want putpets", op.XTelegramAnchor)
+			}
+			if _, ok := op.Extensions["x-telegram-anchor"]; ok {
+				t.Error("extensions with a field of their own must not be duplicated in Extensions")
+			}
+			if got := op.Extensions["x-codegen-request-body-name"]; got != "body" {
+				t.Errorf("operation extension = %v, want body", got)
+			}
+			if _, ok := op.RequestBody.Content["application/vnd.pets+json"]; !ok {
+				t.Error("expected the custom content type")
+			}
+			if got := op.Responses["200"].Ref; got != "#/components/responses/Ok" {
+				t.Errorf("response ref = %q", got)
+			}
+			if got := doc.Components.Schemas["Pet"].Extensions["x-order"]; got != json.Number("12345678901234567890") {
+				t.Errorf("x-order = %#v, want the exact number", got)
+			}
+			if got := doc.Components.Schemas["Pet"].Properties["id"].Extensions["x-go-type"]; got != "int64" {
+				t.Errorf("property extension = %v", got)
+			}
+			if got := doc.Servers[0].Extensions["x-internal"]; got != true {
+				t.Errorf("server extension = %v", got)
+			}
+			if _, ok := doc.Extensions["x-tag-groups"]; !ok {
+				t.Error("expected the document extension x-tag-groups")
+			}
+		})
+	}
+}
+
+// petstoreJSON is a document written by hand rather than by the generator,
+// using the parts of OpenAPI 3.1 the generator never emits.
+const petstoreJSON = `{
+    "openapi": "3.1.0",
+    "info": {
+        "title": "Petstore",
+        "summary": "Pets for sale",
+        "termsOfService": "https://example.com/terms",
+        "contact": {"name": "API team", "email": "api@example.com"},
+        "license": {"name": "Apache 2.0", "identifier": "Apache-2.0"},
+        "version": "2.0.0"
+    },
+    "jsonSchemaDialect": "https://spec.openapis.org/oas/3.1/dialect/base",
+    "servers": [{"url": "https://{region}.example.com", "variables": {"region": {"default": "eu", "enum": ["eu", "us"]}}}],
+    "paths": {
+        "/pets/{petId}": {
+            "summary": "A pet",
+            "parameters": [{"$ref": "#/components/parameters/PetId"}],
+            "get": {
+                "operationId": "getPet",
+                "security": [],
+                "parameters": [{"name": "fields", "in": "query", "style": "form", "explode": false, "schema": {"type": "array", "items": {"type": "string"}}}],
+                "responses": {
+                    "200": {
+                        "description": "The pet",
+                        "headers": {"X-Rate-Limit": {"$ref": "#/components/headers/RateLimit"}},
+                        "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}, "examples": {"rex": {"$ref": "#/components/examples/Rex"}}}},
+                        "links": {"owner": {"$ref": "#/components/links/Owner"}}
+                    }
+                }
+            },
+            "put": {
+                "requestBody": {"$ref": "#/components/requestBodies/Pet"},
+                "callbacks": {"onAdopted": {"$ref": "#/components/callbacks/Adopted"}},
+                "responses": {"204": {"description": "Updated"}}
+            }
+        },
+        "/health": {}
+    },
+    "webhooks": {"newPet": {"$ref": "#/components/pathItems/NewPet"}},
+    "components": {
+        "schemas": {
+            "Pet": {
+                "$id": "https://example.com/pet",
+                "$defs": {"tag": {"type": "string", "maxLength": 20}},
+                "type": "object",
+                "required": ["id"],
+                "properties": {
+                    "id": {"type": "integer", "format": "int64", "readOnly": true},
+                    "kind": {"const": null},
+                    "nickname": {"type": ["string", "null"], "default": null},
+                    "tags": {"type": "array", "prefixItems": [{"$ref": "#/components/schemas/Pet/$defs/tag"}], "contains": {"const": "good"}, "minContains": 1},
+                    "extra": {"type": "object", "properties": {}, "required": []}
+                },
+                "if": {"properties": {"kind": {"const": "cat"}}},
+                "then": {"required": ["lives"]},
+                "else": {"not": {"required": ["lives"]}},
+                "dependentRequired": {"lives": ["kind"]},
+                "unevaluatedProperties": false,
+                "xml": {"name": "pet"},
+                "deprecatedSince": "1.5",
+                "example": {"id": 1}
+            }
+        },
+        "parameters": {"PetId": {"name": "petId", "in": "path", "required": true, "schema": {"type": "integer"}}},
+        "requestBodies": {"Pet": {"description": "A pet", "required": true, "content": {"application/json": {"schema": {"$ref": "#/components/schemas/Pet"}}}}},
+        "headers": {"RateLimit": {"description": "Requests left", "schema": {"type": "integer"}}},
+        "examples": {"Rex": {"summary": "A dog", "value": {"id": 1, "kind": null}}},
+        "links": {"Owner": {"operationId": "getOwner", "parameters": {"ownerId": "$response.body#/ownerId"}}},
+        "callbacks": {
+            "Adopted": {
+                "{$request.body#/callbackUrl}": {"post": {"requestBody": {"content": {"application/json": {}}}, "responses": {"200": {"description": "OK"}}}},
+                "x-internal": true
+            }
+        },
+        "pathItems": {"NewPet": {"post": {"requestBody": {"$ref": "#/components/requestBodies/Pet"}, "responses": {"200": {"description": "OK"}}}}},
+        "securitySchemes": {
+            "oauth": {
+                "type": "oauth2",
+                "flows": {"authorizationCode": {"authorizationUrl": "https://example.com/auth", "tokenUrl": "https://example.com/token", "scopes": {"read": "Read pets"}}}
+            },
+            "oidc": {"type": "openIdConnect", "openIdConnectUrl": "https://example.com/.well-known/openid-configuration"}
+        }
+    },
+    "security": [{"oauth": ["read"]}],
+    "tags": [{"name": "pets", "externalDocs": {"url": "https://example.com/pets"}}],
+    "externalDocs": {"description": "Guide", "url": "https://example.com/guide"}
+}`
+
+// TestLoad_RoundTrip checks that loading and writing a document keeps every
+// key, extensions included, and adds none.
+func TestLoad_RoundTrip(t *testing.T) {
+	for name, input := range map[string]string{"extensions": loadJSON, "petstore": petstoreJSON} {
+		t.Run(name, func(t *testing.T) {
+			doc, err := Load(strings.NewReader(input))
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err := json.Marshal(doc)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			var want, got any
+			dec := json.NewDecoder(strings.NewReader(input))
+			dec.UseNumber()
+			if err := dec.Decode(&want); err != nil {
+				t.Fatal(err)
+			}
+			dec = json.NewDecoder(bytes.NewReader(out))
+			dec.UseNumber()
+			if err := dec.Decode(&got); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("round trip changed the document:\n%s", out)
+			}
+		})
+	}
+}
+
+func TestLoad_Errors(t *testing.T) {
+	for name, input := range map[string]string{
+		"invalid json":      `{"openapi": `,
+		"invalid yaml":      "openapi: [3.1.0",
+		"not openapi":       `{"swagger": "2.0"}`,
+		"empty":             "",
+		"wrong field types": `{"openapi": "3.1.0", "paths": []}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(strings.NewReader(input)); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+}
+
+func TestExtensions_Marshal(t *testing.T) {
+	tag := Tag{Name: "pets", Extensions: Extensions{"x-b": 2, "x-a": "one", "x-display-name": "Pets"}}
+	data, err := json.Marshal(tag)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"name":"pets","x-a":"one","x-b":2,"x-display-name":"Pets"}`; string(data) != want {
+		t.Errorf("Marshal() = %s, want %s", data, want)
+	}
+
+	data, err = json.Marshal(Encoding{Extensions: Extensions{"x-a": 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := `{"x-a":1}`; string(data) != want {
+		t.Errorf("Marshal() of an otherwise empty object = %s, want %s", data, want)
+	}
+}
@@ -0,0 +1,61 @@
+package openapi
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMarshal(t *testing.T) {
+	doc, err := Load(strings.NewReader(loadJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	doc.Info.Description = "First line.\nSecond line."
+	doc.Components.Schemas["Pet"].Properties["id"] = Schema{Type: Types{"integer", "null"}, Minimum: new(float64), Enum: []any{"true", 0.5}}
+
+	t.Run("json", func(t *testing.T) {
+		data, err := Marshal(doc, FormatJSON)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !bytes.HasPrefix(data, []byte("{\n    \"openapi\": \"3.1.0\",\n    \"info\": {")) {
+			t.Errorf("unexpected JSON layout:\n%s", data)
+		}
+	})
+
+	t.Run("yaml", func(t *testing.T) {
+		data, err := Marshal(doc, FormatYAML)
+		if err != nil {
+			t.Fatal(err)
+		}
+		out := string(data)
+		var top []string
+		for _, line := range strings.Split(out, "\n") {
+			if key, _, ok := strings.Cut(line, ":"); ok && line != "" && line[0] != ' ' {
+				top = append(top, key)
+			}
+		}
+		if want := []string{"openapi", "info", "servers", "paths", "components", "x-tag-groups"}; !reflect.DeepEqual(top, want) {
+			t.Errorf("top-level keys = %v, want %v", top, want)
+		}
+		for _, want := range []string{" \"200\":\n", "  description: |-\n    First line.\n    Second line.\n", "- \"true\"\n"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("YAML output lacks %q:\n%s", want, out)
+			}
+		}
+
+		again, err := Load(bytes.NewReader(data))
+		if err != nil {
+			t.Fatalf("Load(YAML output): %v", err)
+		}
+		if !reflect.DeepEqual(again, doc) {
+			t.Errorf("YAML round trip changed the document:\n got %+v\nwant %+v", again, doc)
+		}
+	})
+
+	if _, err := Marshal(doc, "xml"); err == nil {
+		t.Error("Marshal(xml) must fail")
+	}
+}
@@ -1,92 +1,395 @@
 package openapi
 
+// OpenAPI is an OpenAPI 3.1 document. Loading a document and writing it back
+// keeps every field of the specification, including those the generator
+// never sets, and the "x-" extensions of each object.
 type OpenAPI struct {
-	OpenAPI    string                `json:"openapi"`
-	Info       Info                  `json:"info"`
-	Servers    []Server              `json:"servers"`
-	Paths      map[string]Path       `json:"paths"`
-	Components Components            `json:"components,omitempty"`
-	Security   []map[string][]string `json:"security,omitempty"`
+	OpenAPI           string                `json:"openapi"`
+	Info              Info                  `json:"info"`
+	JSONSchemaDialect string                `json:"jsonSchemaDialect,omitempty"`
+	Servers           []Server              `json:"servers,omitempty"`
+	Paths             map[string]Path       `json:"paths,omitempty"`
+	Webhooks          map[string]Path       `json:"webhooks,omitempty"`
+	Components        Components            `json:"components,omitempty"`
+	Security          []map[string][]string `json:"security,omitempty"`
+	Tags              []Tag                 `json:"tags,omitempty"`
+	ExternalDocs      *ExternalDocs         `json:"externalDocs,omitempty"`
+	Extensions        Extensions            `json:"-"`
+}
+
+// Tag groups operations, e.g. by documentation section.
+type Tag struct {
+	Name         string        `json:"name"`
+	Description  string        `json:"description,omitempty"`
+	ExternalDocs *ExternalDocs `json:"externalDocs,omitempty"`
+	Extensions   Extensions    `json:"-"`
 }
 
 type Info struct {
-	Title       string `json:"title,omitempty"`
-	Description string `json:"description,omitempty"`
-	Version     string `json:"version,omitempty"`
+	Title          string     `json:"title,omitempty"`
+	Summary        string     `json:"summary,omitempty"`
+	Description    string     `json:"description,omitempty"`
+	TermsOfService string     `json:"termsOfService,omitempty"`
+	Contact        *Contact   `json:"contact,omitempty"`
+	License        *License   `json:"license,omitempty"`
+	Version        string     `json:"version,omitempty"`
+	Extensions     Extensions `json:"-"`
 }
 
-type Server struct {
-	URL         string     `json:"url"`
-	Description string     `json:"description"`
-	Variables   *Variables `json:"variables,omitempty"`
+type Contact struct {
+	Name       string     `json:"name,omitempty"`
+	URL        string     `json:"url,omitempty"`
+	Email      string     `json:"email,omitempty"`
+	Extensions Extensions `json:"-"`
 }
 
-type Variables struct {
-	Token       *TokenVariable `json:"token,omitempty"`
-	AccessToken *TokenVariable `json:"access_token,omitempty"`
+// License names the license of the API, by SPDX identifier or URL.
+type License struct {
+	Name       string     `json:"name"`
+	Identifier string     `json:"identifier,omitempty"`
+	URL        string     `json:"url,omitempty"`
+	Extensions Extensions `json:"-"`
 }
 
-type TokenVariable struct {
-	Description string `json:"description,omitempty"`
-	Default     string `json:"default,omitempty"`
+type Server struct {
+	URL         string                    `json:"url"`
+	Description string                    `json:"description,omitempty"`
+	Variables   map[string]ServerVariable `json:"variables,omitempty"`
+	Extensions  Extensions                `json:"-"`
+}
+
+// ServerVariable is a variable of a server URL template, such as the bot
+// token in https://api.telegram.org/bot{token}/.
+type ServerVariable struct {
+	Description string     `json:"description,omitempty"`
+	Default     string     `json:"default"`
+	Enum        []string   `json:"enum,omitempty"`
+	Extensions  Extensions `json:"-"`
 }
 
+// Path lists the operations available on a path, one per HTTP method, or
+// refers to a shared path item when Ref is set. Parameters apply to every
+// operation of the path.
 type Path struct {
-	Post Operation `json:"post"`
+	Ref         string      `json:"$ref,omitempty"`
+	Summary     string      `json:"summary,omitempty"`
+	Description string      `json:"description,omitempty"`
+	Get         *Operation  `json:"get,omitempty"`
+	Put         *Operation  `json:"put,omitempty"`
+	Post        *Operation  `json:"post,omitempty"`
+	Delete      *Operation  `json:"delete,omitempty"`
+	Options     *Operation  `json:"options,omitempty"`
+	Head        *Operation  `json:"head,omitempty"`
+	Patch       *Operation  `json:"patch,omitempty"`
+	Trace       *Operation  `json:"trace,omitempty"`
+	Servers     []Server    `json:"servers,omitempty"`
+	Parameters  []Parameter `json:"parameters,omitempty"`
+	Extensions  Extensions  `json:"-"`
 }
 
 type Operation struct {
-	Summary     string              `json:"summary"`
-	Description string              `json:"description"`
-	OperationID string              `json:"operationId"`
-	RequestBody RequestBody         `json:"requestBody"`
-	Responses   map[string]Response `json:"responses"`
+	Tags        []string            `json:"tags,omitempty"`
+	Summary     string              `json:"summary,omitempty"`
+	Description string              `json:"description,omitempty"`
+	OperationID string              `json:"operationId,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses,omitempty"`
+	Callbacks   map[string]Callback `json:"callbacks,omitempty"`
+	Deprecated  bool                `json:"deprecated,omitempty"`
+	// Security overrides the document's security requirements; an empty
+	// list makes the operation public.
+	Security []map[string][]string `json:"security,omitempty"`
+	Servers  []Server              `json:"servers,omitempty"`
+	// XTelegramReplacement names the method that replaces a deprecated one.
+	XTelegramReplacement string `json:"x-telegram-replacement,omitempty"`
+	// ExternalDocs links to the method's documentation; XTelegramAnchor is
+	// the fragment of its heading.
+	ExternalDocs    *ExternalDocs `json:"externalDocs,omitempty"`
+	XTelegramAnchor string        `json:"x-telegram-anchor,omitempty"`
+	// XTelegramSince is the API version that introduced the method.
+	XTelegramSince string     `json:"x-telegram-since,omitempty"`
+	Extensions     Extensions `json:"-"`
+}
+
+// ExternalDocs links an element to its documentation.
+type ExternalDocs struct {
+	Description string     `json:"description,omitempty"`
+	URL         string     `json:"url"`
+	Extensions  Extensions `json:"-"`
 }
 
+// Parameter is a query, path, header or cookie parameter, or a reference to
+// a shared one in components.parameters when Ref is set. Simple values are
+// described by Schema; serialized values such as JSON objects by Content.
+type Parameter struct {
+	Ref             string               `json:"$ref,omitempty"`
+	Name            string               `json:"name,omitempty"`
+	In              string               `json:"in,omitempty"`
+	Description     string               `json:"description,omitempty"`
+	Required        bool                 `json:"required,omitempty"`
+	Deprecated      bool                 `json:"deprecated,omitempty"`
+	AllowEmptyValue bool                 `json:"allowEmptyValue,omitempty"`
+	Style           string               `json:"style,omitempty"`
+	Explode         *bool                `json:"explode,omitempty"`
+	AllowReserved   bool                 `json:"allowReserved,omitempty"`
+	Schema          *Schema              `json:"schema,omitempty"`
+	Example         any                  `json:"example,omitempty"`
+	Examples        map[string]Example   `json:"examples,omitempty"`
+	Content         map[string]MediaType `json:"content,omitempty"`
+	// XTelegramReplacement names the parameter that replaces a deprecated
+	// one.
+	XTelegramReplacement string `json:"x-telegram-replacement,omitempty"`
+	// XTelegramSince is the API version that introduced the parameter.
+	XTelegramSince string     `json:"x-telegram-since,omitempty"`
+	Extensions     Extensions `json:"-"`
+}
+
+// RequestBody maps each accepted content type (e.g. "application/json") to
+// its media type, or refers to a shared body in components.requestBodies
+// when Ref is set.
 type RequestBody struct {
-	Content  MediaType `json:"content"`
-	Required bool      `json:"required"`
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Extensions  Extensions           `json:"-"`
 }
 
+// MediaType describes a body in one content type. Encoding gives the
+// serialization of individual properties of form bodies, keyed by property
+// name.
 type MediaType struct {
-	Applicationjson Applicationjson `json:"application/json,omitempty"`
+	Schema     *Schema             `json:"schema,omitempty"`
+	Example    any                 `json:"example,omitempty"`
+	Examples   map[string]Example  `json:"examples,omitempty"`
+	Encoding   map[string]Encoding `json:"encoding,omitempty"`
+	Extensions Extensions          `json:"-"`
+}
+
+type Encoding struct {
+	ContentType   string            `json:"contentType,omitempty"`
+	Headers       map[string]Header `json:"headers,omitempty"`
+	Style         string            `json:"style,omitempty"`
+	Explode       *bool             `json:"explode,omitempty"`
+	AllowReserved bool              `json:"allowReserved,omitempty"`
+	Extensions    Extensions        `json:"-"`
+}
+
+// Header describes a response or multipart header, or refers to a shared
+// one in components.headers when Ref is set.
+type Header struct {
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Deprecated  bool                 `json:"deprecated,omitempty"`
+	Style       string               `json:"style,omitempty"`
+	Explode     *bool                `json:"explode,omitempty"`
+	Schema      *Schema              `json:"schema,omitempty"`
+	Example     any                  `json:"example,omitempty"`
+	Examples    map[string]Example   `json:"examples,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+	Extensions  Extensions           `json:"-"`
 }
 
-type Applicationjson struct {
-	Schema Schema `json:"schema,omitempty"`
+// Example is an example value, or a reference to a shared one in
+// components.examples when Ref is set.
+type Example struct {
+	Ref           string     `json:"$ref,omitempty"`
+	Summary       string     `json:"summary,omitempty"`
+	Description   string     `json:"description,omitempty"`
+	Value         any        `json:"value,omitempty"`
+	ExternalValue string     `json:"externalValue,omitempty"`
+	Extensions    Extensions `json:"-"`
 }
 
+// Link describes how a value of a response feeds another operation, or
+// refers to a shared link in components.links when Ref is set.
+type Link struct {
+	Ref          string         `json:"$ref,omitempty"`
+	OperationRef string         `json:"operationRef,omitempty"`
+	OperationID  string         `json:"operationId,omitempty"`
+	Parameters   map[string]any `json:"parameters,omitempty"`
+	RequestBody  any            `json:"requestBody,omitempty"`
+	Description  string         `json:"description,omitempty"`
+	Server       *Server        `json:"server,omitempty"`
+	Extensions   Extensions     `json:"-"`
+}
+
+// Schema is a JSON Schema 2020-12 schema, the dialect of OpenAPI 3.1. The
+// same type describes component schemas, properties, array items and
+// parameter schemas. Keywords without a field of their own are kept in
+// Extensions along with the "x-" keys.
 type Schema struct {
-	Type        string              `json:"type,omitempty"`
-	Properties  map[string]Property `json:"properties,omitempty"`
-	Required    []string            `json:"required,omitempty"`
-	Description string              `json:"description,omitempty"`
-	OneOf       []Property          `json:"oneOf,omitempty"`
+	Ref     string            `json:"$ref,omitempty"`
+	Schema  string            `json:"$schema,omitempty"`
+	ID      string            `json:"$id,omitempty"`
+	Anchor  string            `json:"$anchor,omitempty"`
+	Comment string            `json:"$comment,omitempty"`
+	Defs    map[string]Schema `json:"$defs,omitempty"`
+	// Type is one JSON type, or several: ["string", "null"] is a nullable
+	// string.
+	Type        Types  `json:"type,omitempty"`
+	Format      string `json:"format,omitempty"`
+	Title       string `json:"title,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	Items       *Schema           `json:"items,omitempty"`
+	PrefixItems []Schema          `json:"prefixItems,omitempty"`
+	Contains    *Schema           `json:"contains,omitempty"`
+	Properties  map[string]Schema `json:"properties,omitempty"`
+	Required    []string          `json:"required,omitempty"`
+	// AdditionalProperties describes properties not listed in Properties,
+	// e.g. files attached to a multipart body with attach://<name>. Use
+	// BoolSchema(false) to forbid them.
+	AdditionalProperties  *Schema             `json:"additionalProperties,omitempty"`
+	PatternProperties     map[string]Schema   `json:"patternProperties,omitempty"`
+	PropertyNames         *Schema             `json:"propertyNames,omitempty"`
+	UnevaluatedItems      *Schema             `json:"unevaluatedItems,omitempty"`
+	UnevaluatedProperties *Schema             `json:"unevaluatedProperties,omitempty"`
+	DependentRequired     map[string][]string `json:"dependentRequired,omitempty"`
+	DependentSchemas      map[string]Schema   `json:"dependentSchemas,omitempty"`
+
+	OneOf         []Schema       `json:"oneOf,omitempty"`
+	AnyOf         []Schema       `json:"anyOf,omitempty"`
+	AllOf         []Schema       `json:"allOf,omitempty"`
+	Not           *Schema        `json:"not,omitempty"`
+	If            *Schema        `json:"if,omitempty"`
+	Then          *Schema        `json:"then,omitempty"`
+	Else          *Schema        `json:"else,omitempty"`
+	Discriminator *Discriminator `json:"discriminator,omitempty"`
+
+	// Enum, Const, Default and Examples hold decoded JSON values. A null
+	// Const or Default is Null; nil means the keyword is absent.
+	Enum     []any `json:"enum,omitempty"`
+	Const    any   `json:"const,omitempty"`
+	Default  any   `json:"default,omitempty"`
+	Examples []any `json:"examples,omitempty"`
+	// Example is the single example of OpenAPI 3.0, superseded by Examples.
+	Example any `json:"example,omitempty"`
+
+	MinLength        *int     `json:"minLength,omitempty"`
+	MaxLength        *int     `json:"maxLength,omitempty"`
+	Pattern          string   `json:"pattern,omitempty"`
+	Minimum          *float64 `json:"minimum,omitempty"`
+	Maximum          *float64 `json:"maximum,omitempty"`
+	ExclusiveMinimum *float64 `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum *float64 `json:"exclusiveMaximum,omitempty"`
+	MultipleOf       *float64 `json:"multipleOf,omitempty"`
+	MinItems         *int     `json:"minItems,omitempty"`
+	MaxItems         *int     `json:"maxItems,omitempty"`
+	UniqueItems      bool     `json:"uniqueItems,omitempty"`
+	MinContains      *int     `json:"minContains,omitempty"`
+	MaxContains      *int     `json:"maxContains,omitempty"`
+	MinProperties    *int     `json:"minProperties,omitempty"`
+	MaxProperties    *int     `json:"maxProperties,omitempty"`
+
+	ContentEncoding  string  `json:"contentEncoding,omitempty"`
+	ContentMediaType string  `json:"contentMediaType,omitempty"`
+	ContentSchema    *Schema `json:"contentSchema,omitempty"`
+
+	Deprecated bool `json:"deprecated,omitempty"`
+	ReadOnly   bool `json:"readOnly,omitempty"`
+	WriteOnly  bool `json:"writeOnly,omitempty"`
+	XML        *XML `json:"xml,omitempty"`
+
+	// ExternalDocs links to the type's documentation; XTelegramAnchor is the
+	// fragment of its heading.
+	ExternalDocs    *ExternalDocs `json:"externalDocs,omitempty"`
+	XTelegramAnchor string        `json:"x-telegram-anchor,omitempty"`
+	// XTelegramReplacement names the element that replaces a deprecated
+	// one.
+	XTelegramReplacement string `json:"x-telegram-replacement,omitempty"`
+	// XTelegramSince is the API version that introduced the type, field or
+	// parameter.
+	XTelegramSince string     `json:"x-telegram-since,omitempty"`
+	Extensions     Extensions `json:"-"`
+
+	// Boolean, when set, makes this the boolean schema true (anything is
+	// valid) or false (nothing is); the other fields are then ignored.
+	Boolean *bool `json:"-"`
 }
 
-type Property struct {
-	Ref         string     `json:"$ref,omitempty"`
-	Type        string     `json:"type,omitempty"`
-	Items       *Property  `json:"items,omitempty"`
-	OneOf       []Property `json:"oneOf,omitempty"`
-	Description string     `json:"description,omitempty"`
+// XML tunes the XML representation of a property.
+type XML struct {
+	Name       string     `json:"name,omitempty"`
+	Namespace  string     `json:"namespace,omitempty"`
+	Prefix     string     `json:"prefix,omitempty"`
+	Attribute  bool       `json:"attribute,omitempty"`
+	Wrapped    bool       `json:"wrapped,omitempty"`
+	Extensions Extensions `json:"-"`
 }
 
+// Discriminator tells code generators which property selects the variant of a
+// oneOf schema and which schema each of its values maps to.
+type Discriminator struct {
+	PropertyName string            `json:"propertyName"`
+	Mapping      map[string]string `json:"mapping,omitempty"`
+	Extensions   Extensions        `json:"-"`
+}
+
+// Callback maps runtime expressions, such as "{$request.body#/url}", to the
+// path items the API calls back, or refers to a shared callback in
+// components.callbacks when Ref is set.
+type Callback struct {
+	Ref        string
+	Paths      map[string]Path
+	Extensions Extensions
+}
+
+// Response is a response, or a reference to a shared one in
+// components.responses when Ref is set.
 type Response struct {
-	Description string     `json:"description"`
-	Content     *MediaType `json:"content,omitempty"`
+	Ref         string               `json:"$ref,omitempty"`
+	Description string               `json:"description,omitempty"`
+	Headers     map[string]Header    `json:"headers,omitempty"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+	Links       map[string]Link      `json:"links,omitempty"`
+	Extensions  Extensions           `json:"-"`
 }
 
+// Components holds the objects shared by reference, by kind and name.
 type Components struct {
 	Schemas         map[string]Schema         `json:"schemas,omitempty"`
+	Responses       map[string]Response       `json:"responses,omitempty"`
+	Parameters      map[string]Parameter      `json:"parameters,omitempty"`
+	Examples        map[string]Example        `json:"examples,omitempty"`
+	RequestBodies   map[string]RequestBody    `json:"requestBodies,omitempty"`
+	Headers         map[string]Header         `json:"headers,omitempty"`
 	SecuritySchemes map[string]SecurityScheme `json:"securitySchemes,omitempty"`
+	Links           map[string]Link           `json:"links,omitempty"`
+	Callbacks       map[string]Callback       `json:"callbacks,omitempty"`
+	PathItems       map[string]Path           `json:"pathItems,omitempty"`
+	Extensions      Extensions                `json:"-"`
 }
 
+// SecurityScheme is a way to authenticate, or a reference to a shared one
+// in components.securitySchemes when Ref is set.
 type SecurityScheme struct {
-	Type         string `json:"type"`
-	Description  string `json:"description,omitempty"`
-	Name         string `json:"name,omitempty"`
-	In           string `json:"in,omitempty"`
-	Scheme       string `json:"scheme,omitempty"`
-	BearerFormat string `json:"bearerFormat,omitempty"`
+	Ref              string      `json:"$ref,omitempty"`
+	Type             string      `json:"type,omitempty"`
+	Description      string      `json:"description,omitempty"`
+	Name             string      `json:"name,omitempty"`
+	In               string      `json:"in,omitempty"`
+	Scheme           string      `json:"scheme,omitempty"`
+	BearerFormat     string      `json:"bearerFormat,omitempty"`
+	Flows            *OAuthFlows `json:"flows,omitempty"`
+	OpenIDConnectURL string      `json:"openIdConnectUrl,omitempty"`
+	Extensions       Extensions  `json:"-"`
+}
+
+type OAuthFlows struct {
+	Implicit          *OAuthFlow `json:"implicit,omitempty"`
+	Password          *OAuthFlow `json:"password,omitempty"`
+	ClientCredentials *OAuthFlow `json:"clientCredentials,omitempty"`
+	AuthorizationCode *OAuthFlow `json:"authorizationCode,omitempty"`
+	Extensions        Extensions `json:"-"`
+}
+
+type OAuthFlow struct {
+	AuthorizationURL string            `json:"authorizationUrl,omitempty"`
+	TokenURL         string            `json:"tokenUrl,omitempty"`
+	RefreshURL       string            `json:"refreshUrl,omitempty"`
+	Scopes           map[string]string `json:"scopes"`
+	Extensions       Extensions        `json:"-"`
 }
@@ -20,12 +20,12 @@ func TestOpenAPIStructMarshal(t *testing.T) {
 		}},
 		Paths: map[string]Path{
 			"/test": {
-				Post: Operation{
+				Post: &Operation{
 					Summary:     "Test operation",
 					Description: "A test operation",
 					OperationID: "testOp",
-					RequestBody: RequestBody{
-						Content:  MediaType{},
+					RequestBody: &RequestBody{
+						Content:  map[string]MediaType{"application/json": {}},
 						Required: true,
 					},
 					Responses: map[string]Response{},
@@ -44,8 +44,8 @@ func TestServerVariablesOmitEmpty(t *testing.T) {
 	// "variables" object, while a server with a token variable must include it.
 	withVars := Server{
 		URL: "https://api.telegram.org/bot{token}/",
-		Variables: &Variables{
-			Token: &TokenVariable{Description: "Bot token", Default: "123:ABC"},
+		Variables: map[string]ServerVariable{
+			"token": {Description: "Bot token", Default: "123:ABC"},
 		},
 	}
 	withoutVars := Server{URL: "https://gatewayapi.telegram.org/"}
@@ -66,3 +66,21 @@ func TestServerVariablesOmitEmpty(t *testing.T) {
 		t.Errorf("expected variables to be omitted, got %s", b2)
 	}
 }
+
+func TestPathOmitsUnusedMethods(t *testing.T) {
+	op := &Operation{OperationID: "getMe", Responses: map[string]Response{}}
+	b, err := json.Marshal(Path{Get: op, Post: op})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]json.RawMessage
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got["get"] == nil || got["post"] == nil {
+		t.Errorf("expected only get and post, got %s", b)
+	}
+	if strings.Contains(string(b), "requestBody") {
+		t.Errorf("an operation without a body must not emit requestBody, got %s", b)
+	}
+}
@@ -0,0 +1,150 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrUnresolvedRef is returned for references that don't point to a
+// component of the document, including references to other files.
+var ErrUnresolvedRef = errors.New("unresolved reference")
+
+// componentsPrefix starts every local component reference.
+const componentsPrefix = "#/components/"
+
+// Resolve returns the component a local reference such as
+// "#/components/schemas/Message" points to: a Schema, Response, Parameter,
+// Example, RequestBody, Header, SecurityScheme, Link, Callback or Path
+// (for pathItems). The component itself may be another reference; the
+// typed Resolve methods follow those.
+func (o *OpenAPI) Resolve(ref string) (any, error) {
+	kind, name, err := splitRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	var (
+		component any
+		ok        bool
+	)
+	c := &o.Components
+	switch kind {
+	case "schemas":
+		component, ok = c.Schemas[name]
+	case "responses":
+		component, ok = c.Responses[name]
+	case "parameters":
+		component, ok = c.Parameters[name]
+	case "examples":
+		component, ok = c.Examples[name]
+	case "requestBodies":
+		component, ok = c.RequestBodies[name]
+	case "headers":
+		component, ok = c.Headers[name]
+	case "securitySchemes":
+		component, ok = c.SecuritySchemes[name]
+	case "links":
+		component, ok = c.Links[name]
+	case "callbacks":
+		component, ok = c.Callbacks[name]
+	case "pathItems":
+		component, ok = c.PathItems[name]
+	}
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnresolvedRef, ref)
+	}
+	return component, nil
+}
+
+// ResolveSchema returns the schema ref points to, following schemas that
+// are themselves only a reference.
+func (o *OpenAPI) ResolveSchema(ref string) (Schema, error) {
+	if ref == "" {
+		return Schema{}, fmt.Errorf("%w: empty reference", ErrUnresolvedRef)
+	}
+	return resolve(Schema{Ref: ref}, "schemas", o.Components.Schemas, func(s Schema) string { return s.Ref })
+}
+
+// ResolveResponse returns r itself, or the shared response it refers to.
+func (o *OpenAPI) ResolveResponse(r Response) (Response, error) {
+	return resolve(r, "responses", o.Components.Responses, func(r Response) string { return r.Ref })
+}
+
+// ResolveParameter returns p itself, or the shared parameter it refers to.
+func (o *OpenAPI) ResolveParameter(p Parameter) (Parameter, error) {
+	return resolve(p, "parameters", o.Components.Parameters, func(p Parameter) string { return p.Ref })
+}
+
+// ResolveExample returns e itself, or the shared example it refers to.
+func (o *OpenAPI) ResolveExample(e Example) (Example, error) {
+	return resolve(e, "examples", o.Components.Examples, func(e Example) string { return e.Ref })
+}
+
+// ResolveRequestBody returns rb itself, or the shared body it refers to.
+func (o *OpenAPI) ResolveRequestBody(rb RequestBody) (RequestBody, error) {
+	return resolve(rb, "requestBodies", o.Components.RequestBodies, func(rb RequestBody) string { return rb.Ref })
+}
+
+// ResolveHeader returns h itself, or the shared header it refers to.
+func (o *OpenAPI) ResolveHeader(h Header) (Header, error) {
+	return resolve(h, "headers", o.Components.Headers, func(h Header) string { return h.Ref })
+}
+
+// ResolveSecurityScheme returns s itself, or the shared scheme it refers to.
+func (o *OpenAPI) ResolveSecurityScheme(s SecurityScheme) (SecurityScheme, error) {
+	return resolve(s, "securitySchemes", o.Components.SecuritySchemes, func(s SecurityScheme) string { return s.Ref })
+}
+
+// ResolveLink returns l itself, or the shared link it refers to.
+func (o *OpenAPI) ResolveLink(l Link) (Link, error) {
+	return resolve(l, "links", o.Components.Links, func(l Link) string { return l.Ref })
+}
+
+// ResolveCallback returns c itself, or the shared callback it refers to.
+func (o *OpenAPI) ResolveCallback(c Callback) (Callback, error) {
+	return resolve(c, "callbacks", o.Components.Callbacks, func(c Callback) string { return c.Ref })
+}
+
+// ResolvePath returns p itself, or the shared path item in
+// components.pathItems it refers to.
+func (o *OpenAPI) ResolvePath(p Path) (Path, error) {
+	return resolve(p, "pathItems", o.Components.PathItems, func(p Path) string { return p.Ref })
+}
+
+// resolve follows the references of v through the components of kind until
+// it reaches one that is not a reference.
+func resolve[T any](v T, kind string, components map[string]T, refOf func(T) string) (T, error) {
+	var zero T
+	seen := make(map[string]bool)
+	for ref := refOf(v); ref != ""; ref = refOf(v) {
+		if seen[ref] {
+			return zero, fmt.Errorf("%w: circular reference %s", ErrUnresolvedRef, ref)
+		}
+		seen[ref] = true
+		k, name, err := splitRef(ref)
+		if err != nil {
+			return zero, err
+		}
+		shared, ok := components[name]
+		if k != kind || !ok {
+			return zero, fmt.Errorf("%w: %s is not in components.%s", ErrUnresolvedRef, ref, kind)
+		}
+		v = shared
+	}
+	return v, nil
+}
+
+// splitRef splits a local component reference into the component kind and
+// name, unescaping JSON pointer tokens ("~1" is "/", "~0" is "~").
+func splitRef(ref string) (kind, name string, err error) {
+	rest, ok := strings.CutPrefix(ref, componentsPrefix)
+	if !ok {
+		return "", "", fmt.Errorf("%w: %s is not a local component reference", ErrUnresolvedRef, ref)
+	}
+	kind, name, ok = strings.Cut(rest, "/")
+	if !ok || name == "" || strings.Contains(name, "/") {
+		return "", "", fmt.Errorf("%w: malformed reference %s", ErrUnresolvedRef, ref)
+	}
+	name = strings.NewReplacer("~1", "/", "~0", "~").Replace(name)
+	return kind, name, nil
+}
@@ -0,0 +1,109 @@
+package openapi
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestResolve(t *testing.T) {
+	doc := &OpenAPI{Components: Components{
+		Schemas: map[string]Schema{
+			"Message":  {Type: Types{"object"}, Description: "A message."},
+			"Alias":    {Ref: "#/components/schemas/Message"},
+			"Loop":     {Ref: "#/components/schemas/Loop"},
+			"a/b~c":    {Type: Types{"string"}},
+			"Dangling": {Ref: "#/components/schemas/Missing"},
+		},
+		Responses: map[string]Response{
+			"BadRequest": {Description: "Bad request"},
+			"Alias":      {Ref: "#/components/responses/BadRequest"},
+		},
+		SecuritySchemes: map[string]SecurityScheme{"token": {Type: "http"}},
+	}}
+
+	if got, err := doc.Resolve("#/components/securitySchemes/token"); err != nil || got.(SecurityScheme).Type != "http" {
+		t.Errorf("Resolve(securitySchemes) = %v, %v", got, err)
+	}
+	if got, err := doc.ResolveSchema("#/components/schemas/Alias"); err != nil || got.Description != "A message." {
+		t.Errorf("ResolveSchema(Alias) = %+v, %v", got, err)
+	}
+	if got, err := doc.ResolveSchema("#/components/schemas/a~1b~0c"); err != nil || !got.Type.Is("string") {
+		t.Errorf("ResolveSchema(escaped) = %+v, %v", got, err)
+	}
+	if got, err := doc.ResolveResponse(Response{Ref: "#/components/responses/Alias"}); err != nil || got.Description != "Bad request" {
+		t.Errorf("ResolveResponse(Alias) = %+v, %v", got, err)
+	}
+	if got, err := doc.ResolveResponse(Response{Description: "inline"}); err != nil || got.Description != "inline" {
+		t.Errorf("ResolveResponse(inline) = %+v, %v", got, err)
+	}
+
+	for _, ref := range []string{
+		"#/components/schemas/Missing",
+		"#/components/schemas/Loop",
+		"#/components/schemas/Dangling",
+		"#/components/responses/BadRequest",
+		"other.yaml#/components/schemas/Message",
+		"#/components/schemas",
+	} {
+		if _, err := doc.ResolveSchema(ref); !errors.Is(err, ErrUnresolvedRef) {
+			t.Errorf("ResolveSchema(%q) error = %v, want ErrUnresolvedRef", ref, err)
+		}
+	}
+	if _, err := doc.Resolve("#/components/parameters/limit"); !errors.Is(err, ErrUnresolvedRef) {
+		t.Errorf("Resolve(parameters) error = %v, want ErrUnresolvedRef", err)
+	}
+}
+
+func TestResolve_EveryComponentKind(t *testing.T) {
+	doc, err := Load(strings.NewReader(petstoreJSON))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for ref, want := range map[string]string{
+		"#/components/parameters/PetId":     "openapi.Parameter",
+		"#/components/requestBodies/Pet":    "openapi.RequestBody",
+		"#/components/headers/RateLimit":    "openapi.Header",
+		"#/components/examples/Rex":         "openapi.Example",
+		"#/components/links/Owner":          "openapi.Link",
+		"#/components/callbacks/Adopted":    "openapi.Callback",
+		"#/components/pathItems/NewPet":     "openapi.Path",
+		"#/components/securitySchemes/oidc": "openapi.SecurityScheme",
+	} {
+		got, err := doc.Resolve(ref)
+		if err != nil || fmt.Sprintf("%T", got) != want {
+			t.Errorf("Resolve(%s) = %T, %v; want %s", ref, got, err, want)
+		}
+	}
+
+	path := doc.Paths["/pets/{petId}"]
+	if p, err := doc.ResolveParameter(path.Parameters[0]); err != nil || p.Name != "petId" || !p.Required {
+		t.Errorf("ResolveParameter = %+v, %v", p, err)
+	}
+	if rb, err := doc.ResolveRequestBody(*path.Put.RequestBody); err != nil || rb.Description != "A pet" {
+		t.Errorf("ResolveRequestBody = %+v, %v", rb, err)
+	}
+	if cb, err := doc.ResolveCallback(path.Put.Callbacks["onAdopted"]); err != nil || cb.Paths["{$request.body#/callbackUrl}"].Post == nil {
+		t.Errorf("ResolveCallback = %+v, %v", cb, err)
+	}
+	res := path.Get.Responses["200"]
+	if h, err := doc.ResolveHeader(res.Headers["X-Rate-Limit"]); err != nil || h.Description != "Requests left" {
+		t.Errorf("ResolveHeader = %+v, %v", h, err)
+	}
+	if e, err := doc.ResolveExample(res.Content["application/json"].Examples["rex"]); err != nil || e.Summary != "A dog" {
+		t.Errorf("ResolveExample = %+v, %v", e, err)
+	}
+	if l, err := doc.ResolveLink(res.Links["owner"]); err != nil || l.OperationID != "getOwner" {
+		t.Errorf("ResolveLink = %+v, %v", l, err)
+	}
+	if p, err := doc.ResolvePath(doc.Webhooks["newPet"]); err != nil || p.Post == nil {
+		t.Errorf("ResolvePath = %+v, %v", p, err)
+	}
+	if s, err := doc.ResolveSecurityScheme(SecurityScheme{Ref: "#/components/securitySchemes/oauth"}); err != nil || s.Flows.AuthorizationCode.Scopes["read"] == "" {
+		t.Errorf("ResolveSecurityScheme = %+v, %v", s, err)
+	}
+	if _, err := doc.ResolveParameter(Parameter{Ref: "#/components/headers/RateLimit"}); !errors.Is(err, ErrUnresolvedRef) {
+		t.Errorf("ResolveParameter(header ref) error = %v, want ErrUnresolvedRef", err)
+	}
+}
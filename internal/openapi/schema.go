@@ -0,0 +1,102 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Types is the "type" keyword of a schema. A single type is written as a
+// string and several as an array, the way OpenAPI 3.1 marks nullable values:
+// {"type": ["string", "null"]}.
+type Types []string
+
+// Is reports whether t is exactly the single type name.
+func (t Types) Is(name string) bool {
+	return len(t) == 1 && t[0] == name
+}
+
+// Includes reports whether name is one of the types.
+func (t Types) Includes(name string) bool {
+	for _, x := range t {
+		if x == name {
+			return true
+		}
+	}
+	return false
+}
+
+// Nullable reports whether null is one of the types.
+func (t Types) Nullable() bool {
+	return t.Includes("null")
+}
+
+func (t Types) MarshalJSON() ([]byte, error) {
+	if len(t) == 1 {
+		return json.Marshal(t[0])
+	}
+	return json.Marshal([]string(t))
+}
+
+func (t *Types) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		*t = Types{name}
+		return nil
+	}
+	var names []string
+	if err := json.Unmarshal(data, &names); err != nil {
+		return fmt.Errorf("type must be a string or an array of strings: %w", err)
+	}
+	*t = names
+	return nil
+}
+
+// Null is a null Const or Default, e.g. {"const": null}. A nil value means
+// the keyword is absent.
+var Null = json.RawMessage("null")
+
+// BoolSchema returns the boolean schema b, e.g. BoolSchema(false) for
+// "additionalProperties": false.
+func BoolSchema(b bool) *Schema {
+	return &Schema{Boolean: &b}
+}
+
+func (s Schema) MarshalJSON() ([]byte, error) {
+	if s.Boolean != nil {
+		return json.Marshal(*s.Boolean)
+	}
+	type plain Schema
+	return marshalExtended(plain(s), s.Extensions)
+}
+
+func (s *Schema) UnmarshalJSON(data []byte) error {
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] != '{' {
+		var b bool
+		if err := json.Unmarshal(trimmed, &b); err != nil {
+			return fmt.Errorf("schema must be an object or a boolean: %w", err)
+		}
+		*s = Schema{Boolean: &b}
+		return nil
+	}
+	type plain Schema
+	ext, err := unmarshalUnknown(data, (*plain)(s), func(string) bool { return true })
+	if err != nil {
+		return err
+	}
+	s.Extensions = ext
+
+	var nulls struct {
+		Const, Default json.RawMessage
+	}
+	if err := json.Unmarshal(data, &nulls); err != nil {
+		return err
+	}
+	if string(nulls.Const) == "null" {
+		s.Const = Null
+	}
+	if string(nulls.Default) == "null" {
+		s.Default = Null
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package openapi
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestTypes_JSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		types Types
+		json  string
+	}{
+		{"single", Types{"string"}, `"string"`},
+		{"nullable", Types{"string", "null"}, `["string","null"]`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.types)
+			if err != nil || string(data) != tt.json {
+				t.Fatalf("Marshal = %s, %v; want %s", data, err, tt.json)
+			}
+			var got Types
+			if err := json.Unmarshal(data, &got); err != nil || !reflect.DeepEqual(got, tt.types) {
+				t.Errorf("Unmarshal = %v, %v; want %v", got, err, tt.types)
+			}
+		})
+	}
+
+	var bad Types
+	if err := json.Unmarshal([]byte(`42`), &bad); err == nil {
+		t.Error("Unmarshal(42) must fail")
+	}
+	if nullable := (Types{"integer", "null"}); !nullable.Nullable() || nullable.Is("integer") || !nullable.Includes("integer") {
+		t.Errorf("Types%v: Nullable/Is/Includes are wrong", nullable)
+	}
+}
+
+func TestSchema_Keywords(t *testing.T) {
+	in := `{
+		"type": ["object", "null"],
+		"title": "Pet",
+		"properties": {
+			"id": {"type": "integer", "format": "int64", "minimum": 1, "exclusiveMaximum": 100, "multipleOf": 1, "readOnly": true},
+			"name": {"type": "string", "pattern": "^[a-z]+$", "minLength": 1, "default": "rex", "examples": ["rex", "tom"]},
+			"kind": {"enum": ["cat", "dog"], "deprecated": true},
+			"tags": {"type": "array", "items": {"type": "string"}, "uniqueItems": true, "maxItems": 3},
+			"legacy": false
+		},
+		"additionalProperties": false,
+		"allOf": [{"$ref": "#/components/schemas/Base"}],
+		"anyOf": [{"required": ["id"]}, {"required": ["name"]}],
+		"not": {"const": "x"},
+		"minProperties": 1,
+		"x-go-type": "Pet"
+	}`
+	var s Schema
+	if err := json.Unmarshal([]byte(in), &s); err != nil {
+		t.Fatalf("Unmarshal: %v", err)
+	}
+
+	if !s.Type.Nullable() || s.Title != "Pet" || *s.MinProperties != 1 || s.Extensions["x-go-type"] != "Pet" {
+		t.Errorf("top-level keywords not decoded: %+v", s)
+	}
+	if ap := s.AdditionalProperties; ap == nil || ap.Boolean == nil || *ap.Boolean {
+		t.Errorf("additionalProperties = %+v, want false", ap)
+	}
+	if legacy := s.Properties["legacy"]; legacy.Boolean == nil || *legacy.Boolean {
+		t.Errorf("properties.legacy = %+v, want false", legacy)
+	}
+	id := s.Properties["id"]
+	if *id.Minimum != 1 || *id.ExclusiveMaximum != 100 || *id.MultipleOf != 1 || !id.ReadOnly {
+		t.Errorf("properties.id = %+v", id)
+	}
+	name := s.Properties["name"]
+	if name.Pattern != "^[a-z]+$" || name.Default != "rex" || len(name.Examples) != 2 {
+		t.Errorf("properties.name = %+v", name)
+	}
+	if kind := s.Properties["kind"]; len(kind.Enum) != 2 || !kind.Deprecated {
+		t.Errorf("properties.kind = %+v", kind)
+	}
+	if tags := s.Properties["tags"]; !tags.UniqueItems || *tags.MaxItems != 3 || !tags.Items.Type.Is("string") {
+		t.Errorf("properties.tags = %+v", tags)
+	}
+	if len(s.AllOf) != 1 || len(s.AnyOf) != 2 || s.Not == nil || s.Not.Const != "x" {
+		t.Errorf("applicators not decoded: allOf=%v anyOf=%v not=%v", s.AllOf, s.AnyOf, s.Not)
+	}
+
+	// Decoding what was encoded gives the same schema back.
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	for _, want := range []string{`"type":["object","null"]`, `"additionalProperties":false`, `"legacy":false`, `"x-go-type":"Pet"`} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("Marshal output lacks %s: %s", want, data)
+		}
+	}
+	var again Schema
+	if err := json.Unmarshal(data, &again); err != nil || !reflect.DeepEqual(again, s) {
+		t.Errorf("round trip changed the schema:\n got %+v\nwant %+v", again, s)
+	}
+}
+
+func TestSchema_BoolSchema(t *testing.T) {
+	for _, b := range []bool{true, false} {
+		data, err := json.Marshal(Schema{Items: BoolSchema(b)})
+		want := `{"items":` + map[bool]string{true: "true", false: "false"}[b] + `}`
+		if err != nil || string(data) != want {
+			t.Errorf("Marshal = %s, %v; want %s", data, err, want)
+		}
+	}
+
+	var s Schema
+	if err := json.Unmarshal([]byte(`"object"`), &s); err == nil {
+		t.Error("Unmarshal of a string schema must fail")
+	}
+}
@@ -0,0 +1,54 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+)
+
+func TestHeadingAnchors(t *testing.T) {
+	const html = `<!DOCTYPE html><html><body>
+	<h3>Available types</h3>
+	<h4><a class="anchor" name="user" href="#user"><i class="anchor-icon"></i></a>User</h4>
+	<p>This object represents a Telegram user or bot.</p>
+	<table>
+		<thead><tr><th>Field</th><th>Type</th><th>Description</th></tr></thead>
+		<tbody><tr><td>id</td><td>Integer</td><td>Unique identifier.</td></tr></tbody>
+	</table>
+	<h4><a class="anchor" href="#chatfullinfo"><i class="anchor-icon"></i></a>ChatFullInfo</h4>
+	<p>This object contains full information about a chat.</p>
+	<table>
+		<thead><tr><th>Field</th><th>Type</th><th>Description</th></tr></thead>
+		<tbody><tr><td>id</td><td>Integer</td><td>Unique identifier.</td></tr></tbody>
+	</table>
+	<h3>Available methods</h3>
+	<h4><a class="anchor" name="getme" href="#getme"><i class="anchor-icon"></i></a>getMe</h4>
+	<p>Returns basic information about the bot in form of a <a href="#user">User</a> object.</p>
+	<h4>logOut</h4>
+	<p>Use this method to log out. Returns <em>True</em> on success.</p>
+</body></html>`
+
+	page := &PageAPI{Document: docFromHTML(t, html), Types: make(map[string]Type)}
+	if err := page.LoadTypes(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{"user": "user", "chatfullinfo": "chatfullinfo"} {
+		if got := page.Types[name].Anchor; got != want {
+			t.Errorf("%s anchor = %q, want %q", name, got, want)
+		}
+	}
+
+	methods, err := page.GetMethods(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, m := range methods {
+		got[m.Name] = m.Anchor
+	}
+	// logOut has no anchor element, so the lowercased name is used.
+	for name, want := range map[string]string{"getMe": "getme", "logOut": "logout"} {
+		if got[name] != want {
+			t.Errorf("%s anchor = %q, want %q", name, got[name], want)
+		}
+	}
+}
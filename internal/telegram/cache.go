@@ -0,0 +1,88 @@
+package telegram
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/superboomer/tg-spec-cli/internal/fsutil"
+)
+
+// Cache is an on-disk store of fetched documentation pages. Alongside the raw
+// HTML it keeps the HTTP validators (ETag, Last-Modified) so later fetches can
+// be revalidated with a conditional request instead of downloading the page
+// again.
+type Cache struct {
+	Dir string
+}
+
+// cacheEntry is the metadata stored next to a cached page.
+type cacheEntry struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"last_modified,omitempty"`
+	FetchedAt    time.Time `json:"fetched_at"`
+}
+
+// DefaultCacheDir returns the per-user cache directory for documentation pages.
+func DefaultCacheDir() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine user cache directory: %w", err)
+	}
+	return filepath.Join(dir, "tg-spec-cli"), nil
+}
+
+// paths returns the HTML and metadata file paths for url. Entries are keyed by
+// a hash of the URL so arbitrary URLs map to safe file names.
+func (c *Cache) paths(url string) (htmlPath, metaPath string) {
+	sum := sha256.Sum256([]byte(url))
+	key := hex.EncodeToString(sum[:])
+	return filepath.Join(c.Dir, key+".html"), filepath.Join(c.Dir, key+".json")
+}
+
+// load returns the cached entry and page for url. It returns an error wrapping
+// os.ErrNotExist when nothing is cached.
+func (c *Cache) load(url string) (*cacheEntry, []byte, error) {
+	htmlPath, metaPath := c.paths(url)
+
+	meta, err := os.ReadFile(metaPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	var entry cacheEntry
+	if err := json.Unmarshal(meta, &entry); err != nil {
+		return nil, nil, fmt.Errorf("corrupt cache entry %s: %w", metaPath, err)
+	}
+	body, err := os.ReadFile(htmlPath)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &entry, body, nil
+}
+
+// store saves body and its validators for url. Files are written to a
+// temporary name first and renamed into place so a crash never leaves a
+// truncated page behind.
+func (c *Cache) store(entry *cacheEntry, body []byte) error {
+	if err := os.MkdirAll(c.Dir, 0755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	meta, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to encode cache entry: %w", err)
+	}
+
+	htmlPath, metaPath := c.paths(entry.URL)
+	if err := fsutil.WriteFileAtomic(htmlPath, body); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	if err := fsutil.WriteFileAtomic(metaPath, meta); err != nil {
+		return fmt.Errorf("failed to write cache file: %w", err)
+	}
+	return nil
+}
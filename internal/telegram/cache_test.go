@@ -0,0 +1,148 @@
+package telegram
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go.uber.org/zap/zaptest"
+)
+
+// etagServer serves body with a fixed ETag, answering 304 to matching
+// conditional requests. It records how many full and conditional responses
+// it sent.
+func etagServer(t *testing.T, body string) (srv *httptest.Server, full, notModified *int) {
+	t.Helper()
+	full, notModified = new(int), new(int)
+	srv = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			*notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		*full++
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, full, notModified
+}
+
+func readSource(t *testing.T, src Source) string {
+	t.Helper()
+	rc, err := src.Open(context.Background())
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer rc.Close()
+	data, err := io.ReadAll(rc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+func TestHTTPSource_CacheRevalidates(t *testing.T) {
+	srv, full, notModified := etagServer(t, sourceTestPage)
+	src := &HTTPSource{URL: srv.URL, Cache: &Cache{Dir: t.TempDir()}}
+
+	if got := readSource(t, src); got != sourceTestPage {
+		t.Errorf("first fetch = %q", got)
+	}
+	if got := readSource(t, src); got != sourceTestPage {
+		t.Errorf("revalidated fetch = %q, want cached page", got)
+	}
+	if *full != 1 || *notModified != 1 {
+		t.Errorf("expected 1 full and 1 conditional response, got %d and %d", *full, *notModified)
+	}
+}
+
+func TestHTTPSource_SendsIfModifiedSince(t *testing.T) {
+	var gotHeader string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotHeader = r.Header.Get("If-Modified-Since")
+		w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+		_, _ = w.Write([]byte(sourceTestPage))
+	}))
+	t.Cleanup(srv.Close)
+	src := &HTTPSource{URL: srv.URL, Cache: &Cache{Dir: t.TempDir()}}
+
+	readSource(t, src)
+	readSource(t, src)
+	if gotHeader != "Mon, 01 Jan 2024 00:00:00 GMT" {
+		t.Errorf("If-Modified-Since = %q", gotHeader)
+	}
+}
+
+func TestHTTPSource_Offline(t *testing.T) {
+	srv, full, _ := etagServer(t, sourceTestPage)
+	cache := &Cache{Dir: t.TempDir()}
+
+	offline := &HTTPSource{URL: srv.URL, Cache: cache, Offline: true}
+	if _, err := offline.Open(context.Background()); err == nil {
+		t.Error("offline Open() without a cached copy should fail")
+	}
+
+	readSource(t, &HTTPSource{URL: srv.URL, Cache: cache})
+	if got := readSource(t, offline); got != sourceTestPage {
+		t.Errorf("offline fetch = %q, want cached page", got)
+	}
+	if *full != 1 {
+		t.Errorf("offline fetch must not hit the network, got %d requests", *full)
+	}
+
+	if _, err := (&HTTPSource{URL: srv.URL, Offline: true}).Open(context.Background()); err == nil {
+		t.Error("offline Open() without a cache should fail")
+	}
+}
+
+func TestHTTPSource_CorruptCacheEntry(t *testing.T) {
+	srv, full, notModified := etagServer(t, sourceTestPage)
+	cache := &Cache{Dir: t.TempDir()}
+	_, metaPath := cache.paths(srv.URL)
+	if err := os.WriteFile(metaPath, []byte("{not json"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	src := &HTTPSource{URL: srv.URL, Cache: cache, Log: zaptest.NewLogger(t)}
+
+	if got := readSource(t, src); got != sourceTestPage {
+		t.Errorf("fetch with a corrupt cache entry = %q, want the page", got)
+	}
+	if *full != 1 {
+		t.Errorf("a corrupt cache entry should be refetched, got %d full responses", *full)
+	}
+	readSource(t, src)
+	if *notModified != 1 {
+		t.Errorf("the refetched page should replace the corrupt entry, got %d conditional responses", *notModified)
+	}
+}
+
+func TestHTTPSource_CacheWriteFailure(t *testing.T) {
+	srv, _, _ := etagServer(t, sourceTestPage)
+	// A regular file where the cache directory should be makes every write
+	// fail.
+	dir := filepath.Join(t.TempDir(), "cache")
+	if err := os.WriteFile(dir, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	src := &HTTPSource{URL: srv.URL, Cache: &Cache{Dir: dir}, Log: zaptest.NewLogger(t)}
+	if got := readSource(t, src); got != sourceTestPage {
+		t.Errorf("fetch with an unwritable cache = %q, want the page", got)
+	}
+}
+
+func TestDefaultCacheDir(t *testing.T) {
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+	dir, err := DefaultCacheDir()
+	if err != nil {
+		t.Fatalf("DefaultCacheDir() error = %v", err)
+	}
+	if filepath.Base(dir) != "tg-spec-cli" {
+		t.Errorf("DefaultCacheDir() = %q", dir)
+	}
+}
@@ -0,0 +1,152 @@
+package telegram
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Release is an API release listed in the "Recent changes" section.
+type Release struct {
+	// Version is the API version, e.g. "9.2".
+	Version string `json:"version"`
+	// Date is the release date as YYYY-MM-DD, or as written on the page
+	// when it can't be parsed.
+	Date    string   `json:"date"`
+	Changes []Change `json:"changes"`
+}
+
+// Change is one bullet of a release.
+type Change struct {
+	Text string `json:"text"`
+	// Anchors are the fragments of the page elements the change links to,
+	// e.g. "sendmessage" or "replyparameters", in order of appearance.
+	Anchors []string `json:"anchors,omitempty"`
+	// Added lists the elements the change introduces, as far as its
+	// wording tells ("Added the method …", "Added the field … to the
+	// class …").
+	Added []Addition `json:"added,omitempty"`
+}
+
+// releaseVersionRe matches the version line of a release, "Bot API 9.2".
+var releaseVersionRe = regexp.MustCompile(`^\s*Bot API (\d+(?:\.\d+)*)\s*$`)
+
+// releaseDateLayout is the layout of the date headings of the section.
+const releaseDateLayout = "January 2, 2006"
+
+// GetReleases returns the releases of the "Recent changes" section, newest
+// first. Pages without that section, such as the separate changelog page,
+// are read from their first <h4> on.
+func (p *PageAPI) GetReleases() ([]Release, error) {
+	first := p.Document.Find("h3").FilterFunction(func(_ int, s *goquery.Selection) bool {
+		return strings.Contains(strings.ToLower(s.Text()), "recent changes")
+	}).First().Next()
+	if first.Length() == 0 {
+		first = p.Document.Find("h4").First()
+	}
+
+	var releases []Release
+	var date string
+	for s := first; s.Length() > 0 && !s.Is("h3"); s = s.Next() {
+		switch {
+		case s.Is("h4"):
+			date = releaseDate(strings.TrimSpace(s.Text()))
+		case s.Is("p"):
+			if m := releaseVersionRe.FindStringSubmatch(s.Text()); m != nil {
+				releases = append(releases, Release{Version: m[1], Date: date, Changes: []Change{}})
+			}
+		case s.Is("ul"):
+			if len(releases) == 0 {
+				continue
+			}
+			current := &releases[len(releases)-1]
+			s.ChildrenFiltered("li").Each(func(_ int, li *goquery.Selection) {
+				current.Changes = append(current.Changes, Change{
+					Text:    strings.TrimSpace(p.description(li)),
+					Anchors: p.pageAnchors(li),
+					Added:   p.parseAdditions(li),
+				})
+			})
+		}
+	}
+
+	if len(releases) == 0 {
+		return nil, fmt.Errorf("%w: can't find any releases in 'Recent changes'", ErrLayoutChanged)
+	}
+	return releases, nil
+}
+
+// pageAnchors returns the fragments of the links in sel that point to this
+// page, without duplicates.
+func (p *PageAPI) pageAnchors(sel *goquery.Selection) []string {
+	var anchors []string
+	seen := make(map[string]bool)
+	sel.Find("a[href]").Each(func(_ int, a *goquery.Selection) {
+		if anchor, ok := p.pageAnchor(a.AttrOr("href", "")); ok && !seen[anchor] {
+			seen[anchor] = true
+			anchors = append(anchors, anchor)
+		}
+	})
+	return anchors
+}
+
+// pageAnchor returns the fragment of href if it points to this page.
+func (p *PageAPI) pageAnchor(href string) (string, bool) {
+	page, err := url.Parse(p.baseURL())
+	if err != nil {
+		return "", false
+	}
+	ref, err := url.Parse(href)
+	if err != nil || ref.Fragment == "" {
+		return "", false
+	}
+	if target := page.ResolveReference(ref); target.Host != page.Host || target.Path != page.Path {
+		return "", false
+	}
+	return ref.Fragment, true
+}
+
+func releaseDate(heading string) string {
+	if t, err := time.Parse(releaseDateLayout, heading); err == nil {
+		return t.Format(time.DateOnly)
+	}
+	return heading
+}
+
+// CompareVersions compares two dotted API versions numerically, so that
+// "7.10" sorts after "7.9". It returns -1, 0 or 1. Missing components count
+// as zero and non-numeric ones compare as strings.
+func CompareVersions(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		x, y := "0", "0"
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		switch {
+		case xerr == nil && yerr == nil:
+			if xn != yn {
+				if xn < yn {
+					return -1
+				}
+				return 1
+			}
+		case x != y:
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
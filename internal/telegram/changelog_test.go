@@ -0,0 +1,95 @@
+package telegram
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+const changelogPage = `<!DOCTYPE html><html><body>
+	<h3><a class="anchor" name="recent-changes" href="#recent-changes"></a>Recent changes</h3>
+	<blockquote><p>Subscribe to <a href="https://t.me/botnews">@BotNews</a> to be the first to know about the latest updates.</p></blockquote>
+	<h4><a class="anchor" name="august-15-2025" href="#august-15-2025"></a>August 15, 2025</h4>
+	<p><strong>Bot API 9.2</strong></p>
+	<ul>
+		<li>Added the field <em>checklist_task_id</em> to the class <a href="#replyparameters">ReplyParameters</a>.</li>
+		<li>Added the method <a href="#getmystarbalance">getMyStarBalance</a>, see <a href="/bots/webapps#initializing-mini-apps">Mini Apps</a> and <a href="https://core.telegram.org/bots/api#getmystarbalance">getMyStarBalance</a>.</li>
+	</ul>
+	<h4>July 3, 2025</h4>
+	<p><strong>Bot API 9.1</strong></p>
+	<ul>
+		<li>Added the class <a href="#checklist">Checklist</a>.</li>
+	</ul>
+	<p><a href="/bots/api-changelog"><strong>See earlier changes »</strong></a></p>
+	<h3>Authorizing your bot</h3>
+	<p><strong>Bot API 1.0</strong></p>
+	<ul><li>Not a release.</li></ul>
+</body></html>`
+
+func TestGetReleases(t *testing.T) {
+	page := &PageAPI{Document: docFromHTML(t, changelogPage)}
+	got, err := page.GetReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Release{
+		{Version: "9.2", Date: "2025-08-15", Changes: []Change{
+			{Text: "Added the field checklist_task_id to the class ReplyParameters.", Anchors: []string{"replyparameters"},
+				Added: []Addition{{Anchor: "replyparameters", Name: "checklist_task_id"}}},
+			{Text: "Added the method getMyStarBalance, see Mini Apps and getMyStarBalance.", Anchors: []string{"getmystarbalance"},
+				Added: []Addition{{Anchor: "getmystarbalance"}}},
+		}},
+		{Version: "9.1", Date: "2025-07-03", Changes: []Change{
+			{Text: "Added the class Checklist.", Anchors: []string{"checklist"},
+				Added: []Addition{{Anchor: "checklist"}}},
+		}},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("GetReleases() = %+v, want %+v", got, want)
+	}
+}
+
+func TestGetReleases_ChangelogPage(t *testing.T) {
+	// The separate changelog page lists releases without the h3.
+	page := &PageAPI{Document: docFromHTML(t, `<!DOCTYPE html><html><body>
+		<h4>December 29, 2023</h4>
+		<p><strong>Bot API 7.0</strong></p>
+		<ul><li>Added the class <a href="#reactiontype">ReactionType</a>.</li></ul>
+		<h4>Sometime in 2015</h4>
+		<p><strong>Bot API 2.0</strong></p>
+		<ul><li>Inline bots.</li></ul>
+	</body></html>`)}
+	got, err := page.GetReleases()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || got[0].Version != "7.0" || got[1].Date != "Sometime in 2015" {
+		t.Errorf("GetReleases() = %+v", got)
+	}
+}
+
+func TestGetReleases_NoReleases(t *testing.T) {
+	page := &PageAPI{Document: docFromHTML(t, `<html><body><h3>Recent changes</h3></body></html>`)}
+	if _, err := page.GetReleases(); !errors.Is(err, ErrLayoutChanged) {
+		t.Errorf("expected ErrLayoutChanged, got %v", err)
+	}
+}
+
+func TestCompareVersions(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"7.0", "7.0", 0},
+		{"7.10", "7.9", 1},
+		{"6.9", "7.0", -1},
+		{"7", "7.0", 0},
+		{"7.0.1", "7.0", 1},
+		{"7.a", "7.b", -1},
+	}
+	for _, tt := range tests {
+		if got := CompareVersions(tt.a, tt.b); got != tt.want {
+			t.Errorf("CompareVersions(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
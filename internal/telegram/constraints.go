@@ -0,0 +1,234 @@
+package telegram
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Constraints are the numeric, length and size limits documented for a value,
+// e.g. "1-4096 characters after entities parsing" or "Values between 1-100
+// are accepted". Unset limits are nil.
+type Constraints struct {
+	MinLength *int     `json:"min_length,omitempty"`
+	MaxLength *int     `json:"max_length,omitempty"`
+	Minimum   *float64 `json:"minimum,omitempty"`
+	Maximum   *float64 `json:"maximum,omitempty"`
+	MinItems  *int     `json:"min_items,omitempty"`
+	MaxItems  *int     `json:"max_items,omitempty"`
+}
+
+// Report collects parser findings that may need a human look.
+type Report struct {
+	// UnparsedConstraints are constraint-looking phrases that couldn't be
+	// turned into Constraints.
+	UnparsedConstraints []UnparsedPhrase `json:"unparsed_constraints,omitempty"`
+}
+
+// UnparsedPhrase is a phrase the parser didn't understand. Element names the
+// field or parameter as "Type.field" or "method.parameter".
+type UnparsedPhrase struct {
+	Element string `json:"element"`
+	Phrase  string `json:"phrase"`
+}
+
+// valueKind is the broad JSON kind of a declared type, which decides what a
+// documented range constrains.
+type valueKind int
+
+const (
+	kindOther valueKind = iota
+	kindString
+	kindNumber
+	kindArray
+)
+
+// kindOf classifies a declared type. Only single-typed values are classified;
+// alternatives such as "Integer or String" have no unambiguous kind.
+func kindOf(dt DataType) valueKind {
+	switch {
+	case len(dt.Types) != 1:
+		return kindOther
+	case dt.IsArray:
+		return kindArray
+	}
+	switch primitiveOf(dt) {
+	case "String":
+		return kindString
+	case "Integer", "Float":
+		return kindNumber
+	default:
+		return kindOther
+	}
+}
+
+var (
+	// The optional last group of each phrase is the unit word after the
+	// number, e.g. "characters" or "significant bits".
+	rangeRe   = regexp.MustCompile(`(\d+)\s*[-–]\s*(\d+)(\s+(?:significant\s+)?[a-z]+)?`)
+	betweenRe = regexp.MustCompile(`(?i)(?:between|at least) (\d+) and (?:at most |no more than )?(\d+)(\s+(?:significant\s+)?[a-z]+)?`)
+	upToRe    = regexp.MustCompile(`(?i)(?:up to|at most|no more than) (\d+)(\s+(?:significant\s+)?[a-z]+)?`)
+	atLeastRe = regexp.MustCompile(`(?i)at least (\d+)(\s+(?:significant\s+)?[a-z]+)?`)
+	// suspiciousRe matches anything that looks like a limit, used to report
+	// phrases the rules above didn't consume.
+	suspiciousRe = regexp.MustCompile(`(?i)\d+\s*[-–]\s*\d+|up to \d+|between \d+|at most \d+|at least \d+|no more than \d+`)
+)
+
+var (
+	lengthUnits = map[string]bool{"characters": true, "character": true, "symbols": true}
+	itemUnits   = map[string]bool{"items": true, "elements": true}
+	// byteUnits limit the encoded size of a string, which JSON Schema can't
+	// express: minLength and maxLength count characters, and a character
+	// may take up to four bytes in UTF-8. Such limits are understood but
+	// not turned into constraints.
+	byteUnits = map[string]bool{"bytes": true, "byte": true}
+	// bitUnits describe the width of a number ("at most 52 significant
+	// bits"), not its value. Like byteUnits they are understood but never
+	// become constraints.
+	bitUnits = map[string]bool{"bits": true, "significant bits": true}
+	// valueUnits may follow a number whose value is limited, e.g. "between
+	// 5 and 600 seconds". Numbers followed by any other unit word are not
+	// constrained.
+	valueUnits = map[string]bool{"seconds": true, "minutes": true, "hours": true, "days": true}
+	// phraseEnds are words that may follow a bare number without being its
+	// unit, as in "Values between 1-100 are accepted".
+	phraseEnds = map[string]bool{"are": true, "is": true, "and": true, "or": true}
+)
+
+// parseConstraints extracts the limits documented in description for a value
+// of the given kind. It also returns the constraint-looking phrases it could
+// not interpret.
+func parseConstraints(description string, kind valueKind) (*Constraints, []string) {
+	var c Constraints
+	var consumed [][]int
+
+	// apply records the limits of a phrase; lo or hi is nil when the phrase
+	// gives only one bound ("up to 512", "at least 2").
+	apply := func(unit string, lo, hi *int) bool {
+		sizeUnit := lengthUnits[unit] || byteUnits[unit]
+		switch {
+		case bitUnits[unit]:
+		case byteUnits[unit] && kind == kindString:
+		case lengthUnits[unit] && kind == kindString:
+			setBounds(&c.MinLength, &c.MaxLength, lo, hi)
+		case !sizeUnit && kind == kindArray:
+			// "must include 2-10 items", but also "A JSON-serialized list of
+			// 1-100 identifiers of messages".
+			setBounds(&c.MinItems, &c.MaxItems, lo, hi)
+		case kind == kindNumber && (unit == "" || valueUnits[unit] || phraseEnds[unit]):
+			if lo != nil {
+				c.Minimum = toFloat(lo)
+			}
+			if hi != nil {
+				c.Maximum = toFloat(hi)
+			}
+		default:
+			return false
+		}
+		return true
+	}
+
+	for _, m := range rangeRe.FindAllStringSubmatchIndex(description, -1) {
+		if !isStandaloneRange(description, m[0], m[5]) {
+			continue
+		}
+		lo, hi := atoiPtr(description[m[2]:m[3]]), atoiPtr(description[m[4]:m[5]])
+		if apply(unitAt(description, m[6], m[7]), lo, hi) {
+			consumed = append(consumed, m[:2])
+		}
+	}
+	for _, m := range betweenRe.FindAllStringSubmatchIndex(description, -1) {
+		lo, hi := atoiPtr(description[m[2]:m[3]]), atoiPtr(description[m[4]:m[5]])
+		if apply(unitAt(description, m[6], m[7]), lo, hi) {
+			consumed = append(consumed, m[:2])
+		}
+	}
+	for _, m := range upToRe.FindAllStringSubmatchIndex(description, -1) {
+		if apply(unitAt(description, m[4], m[5]), nil, atoiPtr(description[m[2]:m[3]])) {
+			consumed = append(consumed, m[:2])
+		}
+	}
+	for _, m := range atLeastRe.FindAllStringSubmatchIndex(description, -1) {
+		if apply(unitAt(description, m[4], m[5]), atoiPtr(description[m[2]:m[3]]), nil) {
+			consumed = append(consumed, m[:2])
+		}
+	}
+
+	var unparsed []string
+	for _, m := range suspiciousRe.FindAllStringIndex(description, -1) {
+		if !isStandaloneRange(description, m[0], m[1]) || overlaps(consumed, m) {
+			continue
+		}
+		unparsed = append(unparsed, description[m[0]:m[1]])
+	}
+
+	if c == (Constraints{}) {
+		return nil, unparsed
+	}
+	return &c, unparsed
+}
+
+// parseConstraints parses the limits of element and records anything it
+// couldn't interpret in the page report.
+func (p *PageAPI) parseConstraints(element, description string, kind valueKind) *Constraints {
+	c, unparsed := parseConstraints(description, kind)
+	for _, phrase := range unparsed {
+		p.Report.UnparsedConstraints = append(p.Report.UnparsedConstraints, UnparsedPhrase{Element: element, Phrase: phrase})
+	}
+	return c
+}
+
+// isStandaloneRange rejects matches embedded in longer digit runs such as
+// phone numbers ("+1-212-555-0123") or dates.
+func isStandaloneRange(s string, start, end int) bool {
+	if start > 0 && strings.ContainsAny(s[start-1:start], "0123456789+-–") {
+		return false
+	}
+	if end < len(s) && strings.ContainsAny(s[end:end+1], "0123456789-–") {
+		return false
+	}
+	return true
+}
+
+// setBounds stores the non-nil bounds lo and hi in lower and upper, leaving a
+// bound already set by another phrase alone when the new phrase omits it.
+func setBounds(lower, upper **int, lo, hi *int) {
+	if lo != nil {
+		*lower = lo
+	}
+	if hi != nil {
+		*upper = hi
+	}
+}
+
+func unitAt(s string, start, end int) string {
+	if start < 0 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(s[start:end]))
+}
+
+func overlaps(spans [][]int, m []int) bool {
+	for _, s := range spans {
+		if m[0] < s[1] && s[0] < m[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func atoiPtr(s string) *int {
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+func toFloat(n *int) *float64 {
+	if n == nil {
+		return nil
+	}
+	f := float64(*n)
+	return &f
+}
@@ -0,0 +1,198 @@
+package telegram
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func intPtr(n int) *int           { return &n }
+func floatPtr(f float64) *float64 { return &f }
+
+func TestParseConstraints(t *testing.T) {
+	tests := []struct {
+		name         string
+		desc         string
+		kind         valueKind
+		want         *Constraints
+		wantUnparsed []string
+	}{
+		{
+			name: "text length",
+			desc: "Text of the message to be sent, 1-4096 characters after entities parsing",
+			kind: kindString,
+			want: &Constraints{MinLength: intPtr(1), MaxLength: intPtr(4096)},
+		},
+		{
+			name: "caption length from zero",
+			desc: "Photo caption, 0-1024 characters after entities parsing",
+			kind: kindString,
+			want: &Constraints{MinLength: intPtr(0), MaxLength: intPtr(1024)},
+		},
+		{
+			name: "bytes are not characters",
+			desc: "Bot-defined invoice payload, 1-128 bytes.",
+			kind: kindString,
+		},
+		{
+			name: "bytes next to characters",
+			desc: "Text of the button, 1-64 characters; its data, up to 64 bytes",
+			kind: kindString,
+			want: &Constraints{MinLength: intPtr(1), MaxLength: intPtr(64)},
+		},
+		{
+			name: "at least",
+			desc: "A JSON-serialized list of answer options, at least 2 items",
+			kind: kindArray,
+			want: &Constraints{MinItems: intPtr(2)},
+		},
+		{
+			name: "at most",
+			desc: "Optional. The maximum number of winners, at most 100",
+			kind: kindNumber,
+			want: &Constraints{Maximum: floatPtr(100)},
+		},
+		{
+			name: "at least and no more than",
+			desc: "Custom title, at least 1 and no more than 16 characters",
+			kind: kindString,
+			want: &Constraints{MinLength: intPtr(1), MaxLength: intPtr(16)},
+		},
+		{
+			name: "values between",
+			desc: "Limits the number of updates to be retrieved. Values between 1-100 are accepted. Defaults to 100.",
+			kind: kindNumber,
+			want: &Constraints{Minimum: floatPtr(1), Maximum: floatPtr(100)},
+		},
+		{
+			name: "between and",
+			desc: "Duration in seconds, must be between 5 and 600.",
+			kind: kindNumber,
+			want: &Constraints{Minimum: floatPtr(5), Maximum: floatPtr(600)},
+		},
+		{
+			name: "array items",
+			desc: "A JSON-serialized array describing messages to be sent, must include 2-10 items",
+			kind: kindArray,
+			want: &Constraints{MinItems: intPtr(2), MaxItems: intPtr(10)},
+		},
+		{
+			name: "list of identifiers",
+			desc: "A JSON-serialized list of 1-100 identifiers of messages to delete.",
+			kind: kindArray,
+			want: &Constraints{MinItems: intPtr(1), MaxItems: intPtr(100)},
+		},
+		{
+			name: "up to",
+			desc: "Optional. Description of the bot, up to 512 characters",
+			kind: kindString,
+			want: &Constraints{MaxLength: intPtr(512)},
+		},
+		{
+			name: "phone number is not a range",
+			desc: "Type of the entity, e.g. “phone_number” (+1-212-555-0123)",
+			kind: kindString,
+		},
+		{
+			name:         "unit mismatch is reported",
+			desc:         "Emoji on which the dice throw animation is based. Dice can have values 1-6 for “🎲”",
+			kind:         kindString,
+			wantUnparsed: []string{"1-6"},
+		},
+		{
+			name:         "ambiguous kind is reported",
+			desc:         "Unique identifier, 1-64 characters",
+			kind:         kindOther,
+			wantUnparsed: []string{"1-64"},
+		},
+		{
+			name: "significant bits are not a maximum",
+			desc: "Unique identifier for this user or bot. This number may have more than 32 significant bits and some programming languages may have difficulty/silent defects in interpreting it. But it has at most 52 significant bits, so a 64-bit integer or double-precision float type are safe for storing this identifier.",
+			kind: kindNumber,
+		},
+		{
+			name: "value unit",
+			desc: "Period in seconds, 5-600 seconds",
+			kind: kindNumber,
+			want: &Constraints{Minimum: floatPtr(5), Maximum: floatPtr(600)},
+		},
+		{
+			name:         "other units are not a value limit",
+			desc:         "Size of the photo, up to 10 megabytes",
+			kind:         kindNumber,
+			wantUnparsed: []string{"up to 10"},
+		},
+		{
+			name: "no constraints",
+			desc: "Unique identifier for the target chat",
+			kind: kindString,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, unparsed := parseConstraints(tt.desc, tt.kind)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseConstraints() = %+v, want %+v", got, tt.want)
+			}
+			if !reflect.DeepEqual(unparsed, tt.wantUnparsed) {
+				t.Errorf("unparsed = %q, want %q", unparsed, tt.wantUnparsed)
+			}
+		})
+	}
+}
+
+func TestKindOf(t *testing.T) {
+	if k := kindOf(fieldDataType([]string{"Array of PhotoSize"})); k != kindArray {
+		t.Errorf("kindOf(Array of PhotoSize) = %v, want array", k)
+	}
+	if k := kindOf(fieldDataType([]string{"Integer"})); k != kindNumber {
+		t.Errorf("kindOf(Integer) = %v, want number", k)
+	}
+	if k := kindOf(DataType{Types: []string{"Integer", "String"}}); k != kindOther {
+		t.Errorf("kindOf(Integer or String) = %v, want other", k)
+	}
+}
+
+func TestFieldDataType(t *testing.T) {
+	tests := []struct {
+		in   []string
+		want DataType
+	}{
+		{[]string{"String"}, DataType{Types: []string{"String"}}},
+		{[]string{"Array of PhotoSize"}, DataType{Types: []string{"PhotoSize"}, IsArray: true, ArrayDepth: 1}},
+		{[]string{"Array of Array of InlineKeyboardButton"}, DataType{Types: []string{"InlineKeyboardButton"}, IsArray: true, ArrayDepth: 2}},
+		{[]string{"Integer", "String"}, DataType{Types: []string{"Integer", "String"}}},
+	}
+	for _, tt := range tests {
+		if got := fieldDataType(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("fieldDataType(%v) = %+v, want %+v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestGetMethods_ConstraintsAndReport(t *testing.T) {
+	doc := docFromHTML(t, `<!DOCTYPE html><html><body>
+		<h4>sendMessage</h4>
+		<p>Use this method to send text messages.</p>
+		<table>
+			<thead><tr><th>Parameter</th><th>Type</th><th>Required</th><th>Description</th></tr></thead>
+			<tbody>
+				<tr><td>text</td><td>String</td><td>Yes</td><td>Text of the message to be sent, 1-4096 characters after entities parsing</td></tr>
+				<tr><td>emoji</td><td>String</td><td>Optional</td><td>Dice can have values 1-6 for “🎲”</td></tr>
+			</tbody>
+		</table>
+	</body></html>`)
+	page := &PageAPI{Document: doc, Types: map[string]Type{}}
+	methods, err := page.GetMethods(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	c := methods[0].Parameters[0].Constraints
+	if c == nil || *c.MinLength != 1 || *c.MaxLength != 4096 {
+		t.Errorf("text constraints = %+v, want 1-4096 characters", c)
+	}
+	want := []UnparsedPhrase{{Element: "sendMessage.emoji", Phrase: "1-6"}}
+	if !reflect.DeepEqual(page.Report.UnparsedConstraints, want) {
+		t.Errorf("report = %+v, want %+v", page.Report.UnparsedConstraints, want)
+	}
+}
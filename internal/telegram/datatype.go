@@ -6,10 +6,25 @@ import (
 	"github.com/PuerkitoBio/goquery"
 )
 
+// DataType is a parsed parameter type: the type alternatives with any
+// "Array of" prefixes stripped, and how deeply they are nested in arrays.
 type DataType struct {
-	Types      []string
-	IsArray    bool
-	ArrayDepth int
+	Types      []string `json:"types"`
+	IsArray    bool     `json:"is_array,omitempty"`
+	ArrayDepth int      `json:"array_depth,omitempty"`
+}
+
+// fieldDataType returns the DataType of a type-table field, whose type
+// alternatives still carry their "Array of" prefixes.
+func fieldDataType(types []string) DataType {
+	var dt DataType
+	for _, t := range types {
+		if depth := strings.Count(t, "Array of"); depth > dt.ArrayDepth {
+			dt.IsArray, dt.ArrayDepth = true, depth
+		}
+		dt.Types = append(dt.Types, strings.TrimSpace(stripArrayOf(t)))
+	}
+	return dt
 }
 
 func (p *PageAPI) parseDataType(doc *goquery.Selection) DataType {
@@ -0,0 +1,64 @@
+package telegram
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// defaultRe matches "Defaults to …" phrases. The value is either quoted
+// (“🎲”), a number (100, 0.5) or a bare word (true).
+var defaultRe = regexp.MustCompile(`(?i)\bdefaults to (?:“([^”]*)”|(-?\d+(?:\.\d+)?)\b|([a-z_]+)\b)`)
+
+// parseDefault returns the default documented in description converted to
+// the JSON type of the declared primitive (see primitiveOf), or nil if there
+// is none or it doesn't fit the type.
+func parseDefault(description, primitive string) any {
+	m := defaultRe.FindStringSubmatch(description)
+	if m == nil {
+		return nil
+	}
+	quoted, number, word := m[1], m[2], m[3]
+
+	switch primitive {
+	case "Integer":
+		if n, err := strconv.ParseInt(number, 10, 64); err == nil {
+			return n
+		}
+	case "Float":
+		if f, err := strconv.ParseFloat(number, 64); err == nil {
+			return f
+		}
+	case "Boolean":
+		if b, err := strconv.ParseBool(strings.ToLower(word)); err == nil {
+			return b
+		}
+	case "String":
+		// Bare words are too often prose ("Defaults to the current
+		// time"), so only quoted values count for strings.
+		if strings.Contains(m[0], "“") {
+			return quoted
+		}
+	}
+	return nil
+}
+
+// primitiveOf returns the canonical primitive name ("Integer", "Float",
+// "Boolean" or "String") of a single, non-array declared type, or "".
+func primitiveOf(dt DataType) string {
+	if len(dt.Types) != 1 || dt.IsArray {
+		return ""
+	}
+	switch strings.TrimSpace(dt.Types[0]) {
+	case "Integer", "Int":
+		return "Integer"
+	case "Float", "Double":
+		return "Float"
+	case "Boolean", "Bool", "True", "False":
+		return "Boolean"
+	case "String":
+		return "String"
+	default:
+		return ""
+	}
+}
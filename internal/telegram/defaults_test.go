@@ -0,0 +1,104 @@
+package telegram
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParseDefault(t *testing.T) {
+	tests := []struct {
+		name      string
+		desc      string
+		primitive string
+		want      any
+	}{
+		{"integer", "Limits the number of updates to be retrieved. Values between 1-100 are accepted. Defaults to 100.", "Integer", int64(100)},
+		{"integer zero", "Timeout in seconds for long polling. Defaults to 0, i.e. usual short polling.", "Integer", int64(0)},
+		{"float", "Relative scale. Defaults to 0.5", "Float", 0.5},
+		{"boolean", "Pass True to allow. Defaults to false.", "Boolean", false},
+		{"quoted string", "Emoji on which the dice throw animation is based. Defaults to “🎲”", "String", "🎲"},
+		{"bare word string", "Defaults to the current time", "String", nil},
+		{"mismatched type", "Defaults to the value of the previous call", "Integer", nil},
+		{"no default", "Unique identifier for the target chat", "Integer", nil},
+		{"unsupported type", "Defaults to 100.", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseDefault(tt.desc, tt.primitive); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseDefault() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrimitiveOf(t *testing.T) {
+	tests := []struct {
+		types   []string
+		isArray bool
+		want    string
+	}{
+		{[]string{"Integer"}, false, "Integer"},
+		{[]string{"Float"}, false, "Float"},
+		{[]string{"True"}, false, "Boolean"},
+		{[]string{"String"}, false, "String"},
+		{[]string{"String"}, true, ""},
+		{[]string{"Integer", "String"}, false, ""},
+		{[]string{"Message"}, false, ""},
+	}
+	for _, tt := range tests {
+		if got := primitiveOf(DataType{Types: tt.types, IsArray: tt.isArray}); got != tt.want {
+			t.Errorf("primitiveOf(%v, %v) = %q, want %q", tt.types, tt.isArray, got, tt.want)
+		}
+	}
+}
+
+func TestGetMethods_Defaults(t *testing.T) {
+	doc := docFromHTML(t, `<!DOCTYPE html><html><body>
+		<h4>getUpdates</h4>
+		<p>Use this method to receive incoming updates.</p>
+		<table>
+			<thead><tr><th>Parameter</th><th>Type</th><th>Required</th><th>Description</th></tr></thead>
+			<tbody>
+				<tr><td>limit</td><td>Integer</td><td>Optional</td><td>Values between 1-100 are accepted. Defaults to 100.</td></tr>
+				<tr><td>emoji</td><td>String</td><td>Optional</td><td>Defaults to “🎲”</td></tr>
+			</tbody>
+		</table>
+	</body></html>`)
+	page := &PageAPI{Document: doc, Types: map[string]Type{}}
+	methods, err := page.GetMethods(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := methods[0].Parameters
+	if params[0].Default != int64(100) {
+		t.Errorf("limit default = %#v, want 100", params[0].Default)
+	}
+	if params[1].Default != "🎲" {
+		t.Errorf("emoji default = %#v, want 🎲", params[1].Default)
+	}
+}
+
+func TestLoadTypes_Defaults(t *testing.T) {
+	doc := docFromHTML(t, `<!DOCTYPE html><html><body>
+		<h4>InputSticker</h4>
+		<table>
+			<thead><tr><th>Field</th><th>Type</th><th>Description</th></tr></thead>
+			<tbody>
+				<tr><td>scale</td><td>Float</td><td>Optional. Defaults to 0.5</td></tr>
+				<tr><td>keywords</td><td>Array of String</td><td>Optional. Defaults to 1</td></tr>
+			</tbody>
+		</table>
+	</body></html>`)
+	page := &PageAPI{Document: doc, Types: make(map[string]Type)}
+	if err := page.LoadTypes(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	fields := page.Types["inputsticker"].Fields
+	if fields[0].Default != 0.5 {
+		t.Errorf("scale default = %#v, want 0.5", fields[0].Default)
+	}
+	if fields[1].Default != nil {
+		t.Errorf("keywords default = %#v, want nil", fields[1].Default)
+	}
+}
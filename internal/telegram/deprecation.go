@@ -0,0 +1,30 @@
+package telegram
+
+import "regexp"
+
+var (
+	// deprecatedRe matches the ways the docs retire an element, all at the
+	// start of the description (after the optional "Optional." prefix):
+	// "Deprecated.", "This field is deprecated" or "For backward
+	// compatibility". Mentions elsewhere, such as "replaces the deprecated
+	// field", describe other elements.
+	deprecatedRe = regexp.MustCompile(`(?i)^\s*(?:optional\.\s*)?(?:deprecated\.|this (?:field|parameter|method|object) is deprecated\b|for backward compatibility)`)
+	// replacementRe captures the successor named in "Use X instead" or
+	// "replaced by the field X".
+	replacementRe = regexp.MustCompile(`(?i)\buse (?:the (?:field|parameter|method) )?(\w+) instead\b|\breplaced (?:by|with) (?:the (?:field|parameter|method) )?(\w+)`)
+)
+
+// parseDeprecation reports whether description marks its element as
+// deprecated and, if so, the name of the replacement it suggests, if any.
+func parseDeprecation(description string) (deprecated bool, replacement string) {
+	if !deprecatedRe.MatchString(description) {
+		return false, ""
+	}
+	if m := replacementRe.FindStringSubmatch(description); m != nil {
+		replacement = m[1]
+		if replacement == "" {
+			replacement = m[2]
+		}
+	}
+	return true, replacement
+}
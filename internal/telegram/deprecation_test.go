@@ -0,0 +1,64 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+)
+
+func TestParseDeprecation(t *testing.T) {
+	tests := []struct {
+		name            string
+		desc            string
+		wantDeprecated  bool
+		wantReplacement string
+	}{
+		{"use instead", "Deprecated. Use link_preview_options instead.", true, "link_preview_options"},
+		{"optional prefix", "Optional. Deprecated. Use the parameter reply_parameters instead", true, "reply_parameters"},
+		{"backward compatibility", "Optional. For backward compatibility, this field is kept; replaced by the field thumbnail", true, "thumbnail"},
+		{"no hint", "This field is deprecated and will be removed", true, ""},
+		{"not deprecated", "Unique identifier for the target chat", false, ""},
+		{"incidental mention", "Pass True to keep the old format for backward compatibility reasons", false, ""},
+		{"mentions another deprecated field", "New name of the chat. Replaces the deprecated field title; use it instead", false, ""},
+		{"deprecated later in the description", "Identifier of the sticker set. Deprecated sets can't be used", false, ""},
+		{"deprecated as a word", "True, if the poll uses the deprecated format", false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deprecated, replacement := parseDeprecation(tt.desc)
+			if deprecated != tt.wantDeprecated || replacement != tt.wantReplacement {
+				t.Errorf("parseDeprecation() = %v, %q, want %v, %q", deprecated, replacement, tt.wantDeprecated, tt.wantReplacement)
+			}
+		})
+	}
+}
+
+func TestGetMethods_Deprecation(t *testing.T) {
+	doc := docFromHTML(t, `<!DOCTYPE html><html><body>
+		<h4>sendMessage</h4>
+		<p>Use this method to send text messages.</p>
+		<table>
+			<thead><tr><th>Parameter</th><th>Type</th><th>Required</th><th>Description</th></tr></thead>
+			<tbody>
+				<tr><td>disable_web_page_preview</td><td>Boolean</td><td>Optional</td><td>Deprecated. Use link_preview_options instead.</td></tr>
+				<tr><td>text</td><td>String</td><td>Yes</td><td>Text of the message</td></tr>
+			</tbody>
+		</table>
+		<h4>getChatMembersCount</h4>
+		<p>This method is deprecated. Use getChatMemberCount instead.</p>
+	</body></html>`)
+	page := &PageAPI{Document: doc, Types: map[string]Type{}}
+	methods, err := page.GetMethods(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	params := methods[0].Parameters
+	if !params[0].Deprecated || params[0].Replacement != "link_preview_options" {
+		t.Errorf("disable_web_page_preview = %+v, want deprecated in favour of link_preview_options", params[0])
+	}
+	if params[1].Deprecated {
+		t.Error("text must not be deprecated")
+	}
+	if m := methods[1]; !m.Deprecated || m.Replacement != "getChatMemberCount" {
+		t.Errorf("getChatMembersCount = %+v, want deprecated in favour of getChatMemberCount", m)
+	}
+}
@@ -1,25 +1,60 @@
 package telegram
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"os"
 	"time"
 
 	"github.com/PuerkitoBio/goquery"
+	"go.uber.org/zap"
 )
 
-// httpClient is used to fetch documentation pages. It enforces a timeout so a
-// slow or unresponsive server can't hang the CLI indefinitely.
-var httpClient = &http.Client{Timeout: 30 * time.Second}
+// httpClient is used to fetch documentation pages. It sets no timeout of its
+// own: requests carry the caller's context, so --timeout and Ctrl-C bound
+// them without capping a slow but progressing download.
+var httpClient = &http.Client{}
 
 type PageAPI struct {
 	Types    map[string]Type
 	Document *goquery.Document
+	// Report collects findings from parsing that may need a human look.
+	Report Report
+	// DescriptionFormat selects how descriptions are stored: plain text
+	// (the default) or Markdown, see DescriptionPlain and DescriptionMarkdown.
+	DescriptionFormat string
+	// BaseURL is the public URL of the page, used to make links in Markdown
+	// descriptions absolute. Defaults to DefaultDocsURL.
+	BaseURL string
 }
 
-func GetPage(urlStr string) (*PageAPI, error) {
-	parsedURL, err := url.Parse(urlStr)
+// HTTPSource fetches the documentation page over HTTP(S). Transient failures
+// are retried according to Retry.
+//
+// When Cache is set, fetched pages are stored on disk and later requests are
+// sent as conditional requests (If-None-Match / If-Modified-Since); a 304
+// answer is served from the cache. Offline skips the network entirely and
+// only serves cached pages. The cache is best effort: an unreadable entry is
+// fetched again and a failed write is logged to Log (if set), not returned.
+type HTTPSource struct {
+	URL     string
+	Cache   *Cache
+	Offline bool
+	Retry   RetryPolicy
+	Log     *zap.Logger
+}
+
+func (s *HTTPSource) String() string {
+	return s.URL
+}
+
+func (s *HTTPSource) Open(ctx context.Context) (io.ReadCloser, error) {
+	parsedURL, err := url.Parse(s.URL)
 	if err != nil {
 		return nil, fmt.Errorf("invalid URL: %w", err)
 	}
@@ -30,16 +65,123 @@ func GetPage(urlStr string) (*PageAPI, error) {
 		return nil, fmt.Errorf("URL must have a host")
 	}
 
-	res, err := httpClient.Get(parsedURL.String())
+	var cached *cacheEntry
+	var cachedBody []byte
+	if s.Cache != nil {
+		cached, cachedBody, err = s.Cache.load(s.URL)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			s.log().Warn("ignoring unreadable cache entry", zap.String("url", s.URL), zap.Error(err))
+		}
+	}
+
+	if s.Offline {
+		if s.Cache == nil {
+			return nil, errors.New("offline mode requires a cache")
+		}
+		if cached == nil {
+			return nil, fmt.Errorf("no cached copy of %s available in offline mode", s.URL)
+		}
+		return io.NopCloser(bytes.NewReader(cachedBody)), nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsedURL.String(), http.NoBody)
+	if err != nil {
+		return nil, fmt.Errorf("invalid request: %w", err)
+	}
+	if cached != nil {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	res, err := s.fetch(ctx, req)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotModified && cached != nil {
+		res.Body.Close()
+		return io.NopCloser(bytes.NewReader(cachedBody)), nil
+	}
 	if res.StatusCode != 200 {
-		return nil, fmt.Errorf("status code error: %d %s", res.StatusCode, res.Status)
+		res.Body.Close()
+		return nil, &FetchError{URL: s.URL, StatusCode: res.StatusCode, Status: res.Status}
+	}
+	if s.Cache == nil {
+		return res.Body, nil
+	}
+
+	defer res.Body.Close()
+	body, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, &FetchError{URL: s.URL, Err: err}
+	}
+	entry := &cacheEntry{
+		URL:          s.URL,
+		ETag:         res.Header.Get("ETag"),
+		LastModified: res.Header.Get("Last-Modified"),
+		FetchedAt:    time.Now().UTC(),
+	}
+	if err := s.Cache.store(entry, body); err != nil {
+		s.log().Warn("failed to cache documentation page", zap.String("url", s.URL), zap.Error(err))
+	}
+	return io.NopCloser(bytes.NewReader(body)), nil
+}
+
+func (s *HTTPSource) log() *zap.Logger {
+	if s.Log == nil {
+		return zap.NewNop()
+	}
+	return s.Log
+}
+
+// fetch sends req, retrying transient failures until ctx is done. Any response
+// other than 200 or 304 is turned into a *FetchError.
+func (s *HTTPSource) fetch(ctx context.Context, req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		res, err := httpClient.Do(req)
+		var fetchErr *FetchError
+		var retryAfter time.Duration
+		switch {
+		case err != nil:
+			fetchErr = &FetchError{URL: s.URL, Err: err}
+		case res.StatusCode == http.StatusOK || res.StatusCode == http.StatusNotModified:
+			return res, nil
+		default:
+			retryAfter = parseRetryAfter(res.Header.Get("Retry-After"), time.Now())
+			res.Body.Close()
+			fetchErr = &FetchError{URL: s.URL, StatusCode: res.StatusCode, Status: res.Status}
+		}
+
+		if attempt >= s.Retry.MaxRetries || !fetchErr.Temporary() || ctx.Err() != nil {
+			return nil, fetchErr
+		}
+		if err := sleep(ctx, s.Retry.delay(attempt, retryAfter)); err != nil {
+			return nil, err
+		}
 	}
+}
+
+// GetPage fetches and parses the documentation page at urlStr.
+func GetPage(ctx context.Context, urlStr string) (*PageAPI, error) {
+	return LoadPage(ctx, &HTTPSource{URL: urlStr})
+}
+
+// LoadPage reads the documentation page from src and parses it.
+func LoadPage(ctx context.Context, src Source) (*PageAPI, error) {
+	rc, err := src.Open(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return NewPage(rc)
+}
 
-	doc, err := goquery.NewDocumentFromReader(res.Body)
+// NewPage parses a documentation page from r.
+func NewPage(r io.Reader) (*PageAPI, error) {
+	doc, err := goquery.NewDocumentFromReader(r)
 	if err != nil {
 		return nil, err
 	}
@@ -1,6 +1,9 @@
 package telegram
 
-import "testing"
+import (
+	"context"
+	"testing"
+)
 
 func TestGetPage_URLValidation(t *testing.T) {
 	tests := []struct {
@@ -13,7 +16,7 @@ func TestGetPage_URLValidation(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if _, err := GetPage(tt.url); err == nil {
+			if _, err := GetPage(context.Background(), tt.url); err == nil {
 				t.Errorf("GetPage(%q) expected an error", tt.url)
 			}
 		})
@@ -24,7 +27,7 @@ func TestGetTypes_AlreadyLoaded(t *testing.T) {
 	// When types are already populated, GetTypes returns them without parsing
 	// (Document is nil here, so any parsing attempt would panic).
 	page := &PageAPI{Types: map[string]Type{"user": {Name: "User"}}}
-	types, err := page.GetTypes()
+	types, err := page.GetTypes(context.Background())
 	if err != nil {
 		t.Fatalf("GetTypes() error = %v", err)
 	}
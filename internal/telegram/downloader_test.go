@@ -1,6 +1,7 @@
 package telegram
 
 import (
+	"context"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -40,7 +41,7 @@ func TestGetPage(t *testing.T) {
 			}))
 			defer server.Close()
 
-			got, err := GetPage(server.URL)
+			got, err := GetPage(context.Background(), server.URL)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("GetPage() error = %v, wantErr %v", err, tt.wantErr)
 				return
@@ -53,7 +54,7 @@ func TestGetPage(t *testing.T) {
 
 	// Test error cases
 	t.Run("invalid url", func(t *testing.T) {
-		_, err := GetPage("invalid-url")
+		_, err := GetPage(context.Background(), "invalid-url")
 		if err == nil {
 			t.Error("GetPage() with invalid URL should return error")
 		}
@@ -65,7 +66,7 @@ func TestGetPage(t *testing.T) {
 		}))
 		defer server.Close()
 
-		_, err := GetPage(server.URL)
+		_, err := GetPage(context.Background(), server.URL)
 		if err == nil {
 			t.Error("GetPage() with server error should return error")
 		}
@@ -0,0 +1,129 @@
+package telegram
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// enumTriggers are the phrases that introduce a list of allowed values in a
+// description, e.g. "Type of chat, can be either “private”, “group”,
+// “supergroup” or “channel”". A trigger only counts when a quoted value
+// follows it directly, so prose like "can be edited" never starts a list.
+var enumTriggers = []string{
+	"can be either",
+	"can be one of",
+	"must be one of",
+	"one of",
+	"can be",
+}
+
+// knownEnums lists the values of parameters and fields whose descriptions
+// refer to another section instead of spelling the values out. They are
+// applied by name, and only when the description carries the reference in
+// seeAlso, so an unrelated field that happens to share the name is left
+// alone. The values are copied from that section and must be updated by hand
+// when Telegram adds one: parse_mode points to "formatting options", which
+// defines MarkdownV2, HTML and the legacy Markdown mode.
+var knownEnums = map[string]struct {
+	seeAlso string
+	values  []string
+}{
+	"parse_mode": {seeAlso: "formatting options", values: []string{"MarkdownV2", "HTML", "Markdown"}},
+}
+
+var (
+	quotedValueRe = regexp.MustCompile(`“([^”]+)”`)
+
+	// constRes match the phrasings used for fixed discriminator values:
+	// "always “creator”" and "must be “photo”". The docs usually write the
+	// latter as "must be <em>photo</em>"; quoteEmphasis turns that into the
+	// quoted form, so plain words ("must be unique") never match.
+	constRes = []*regexp.Regexp{
+		regexp.MustCompile(`\balways “([^”]+)”`),
+		regexp.MustCompile(`\bmust be “([^”]+)”`),
+	}
+)
+
+// parseEnum returns the allowed values of a string field or parameter called
+// name, or nil if the description doesn't list any. A list needs at least two
+// quoted values in the sentence of one of enumTriggers, the first of them
+// right after the trigger.
+func parseEnum(name, description string) []string {
+	if known, ok := knownEnums[name]; ok && strings.Contains(strings.ToLower(description), known.seeAlso) {
+		return append([]string(nil), known.values...)
+	}
+
+	for _, sentence := range sentences(description) {
+		lower := strings.ToLower(sentence)
+		for _, trigger := range enumTriggers {
+			idx := strings.Index(lower, trigger)
+			if idx == -1 {
+				continue
+			}
+			rest := sentence[idx+len(trigger):]
+			if !strings.HasPrefix(strings.TrimLeft(rest, " "), "“") {
+				continue
+			}
+			var values []string
+			for _, m := range quotedValueRe.FindAllStringSubmatch(rest, -1) {
+				if !containsString(values, m[1]) {
+					values = append(values, m[1])
+				}
+			}
+			if len(values) >= 2 {
+				return values
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// isStringType reports whether types declares a plain (possibly array) String,
+// the only kind of value enums are extracted for.
+func isStringType(types []string) bool {
+	return len(types) == 1 && strings.TrimSpace(stripArrayOf(types[0])) == "String"
+}
+
+// parseConst returns the single fixed value documented for a string field,
+// such as the "type" of a union variant ("Type of the result, must be
+// “photo”" or "The member's status in the chat, always “creator”"), or "" if
+// none. Pass descriptions taken from the page through quoteEmphasis first.
+func parseConst(description string) string {
+	description = strings.TrimSpace(description)
+	for _, re := range constRes {
+		if m := re.FindStringSubmatch(description); m != nil {
+			return m[1]
+		}
+	}
+	return ""
+}
+
+// quoteEmphasis returns the text of sel with the contents of <em> elements
+// wrapped in typographic quotes, the way the docs quote literal values
+// elsewhere: "must be <em>photo</em>" becomes "must be “photo”".
+func quoteEmphasis(sel *goquery.Selection) string {
+	var b strings.Builder
+	for _, n := range sel.Nodes {
+		writeQuotedText(&b, n)
+	}
+	return b.String()
+}
+
+func writeQuotedText(b *strings.Builder, n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case c.Type == html.TextNode:
+			b.WriteString(c.Data)
+		case c.Type == html.ElementNode && c.Data == "em":
+			b.WriteString("“")
+			writeQuotedText(b, c)
+			b.WriteString("”")
+		default:
+			writeQuotedText(b, c)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package telegram
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestParseEnum(t *testing.T) {
+	tests := []struct {
+		name string
+		desc string
+		want []string
+	}{
+		{
+			name: "type",
+			desc: "Type of the chat, can be either “private”, “group”, “supergroup” or “channel”",
+			want: []string{"private", "group", "supergroup", "channel"},
+		},
+		{
+			name: "type",
+			desc: "Type of the entity. Currently, can be “mention” (@username), “hashtag” (#hashtag or #hashtag@chatusername), “url” (https://telegram.org)",
+			want: []string{"mention", "hashtag", "url"},
+		},
+		{
+			name: "emoji",
+			desc: "Emoji on which the dice throw animation is based. Currently, must be one of “🎲”, “🎯”, “🏀”, “⚽”, “🎳”, or “🎰”. Dice can have values 1-6 for “🎲”, “🎯” and “🎳”. Defaults to “🎲”",
+			want: []string{"🎲", "🎯", "🏀", "⚽", "🎳", "🎰"},
+		},
+		{
+			name: "type",
+			desc: "Type of the sticker, currently one of “regular”, “mask”, “custom_emoji”.",
+			want: []string{"regular", "mask", "custom_emoji"},
+		},
+		{
+			name: "parse_mode",
+			desc: "Mode for parsing entities in the message text. See formatting options for more details.",
+			want: []string{"MarkdownV2", "HTML", "Markdown"},
+		},
+		{
+			name: "status",
+			desc: "The member's status in the chat, always “creator”",
+			want: nil, // a single fixed value is a constant, not an enum
+		},
+		{
+			name: "text",
+			desc: "Text of the message to be sent, 1-4096 characters after entities parsing",
+			want: nil,
+		},
+		{
+			name: "caption",
+			desc: "Use “bold” and “italic” for emphasis.", // quoted values without a trigger
+			want: nil,
+		},
+		{
+			name: "text",
+			desc: "The text can be formatted, e.g. with “bold” or “italic” entities.", // trigger not followed by a value
+			want: nil,
+		},
+		{
+			name: "parse_mode",
+			desc: "Mode of the message, can be “plain” or “rich”.", // not a reference to formatting options
+			want: []string{"plain", "rich"},
+		},
+		{
+			name: "type",
+			desc: "Type of the result! Can be “a” or “b”",
+			want: []string{"a", "b"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := parseEnum(tt.name, tt.desc); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseEnum() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsStringType(t *testing.T) {
+	tests := []struct {
+		in   []string
+		want bool
+	}{
+		{[]string{"String"}, true},
+		{[]string{"Array of String"}, true},
+		{[]string{"Integer"}, false},
+		{[]string{"Integer", "String"}, false},
+		{nil, false},
+	}
+	for _, tt := range tests {
+		if got := isStringType(tt.in); got != tt.want {
+			t.Errorf("isStringType(%v) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestParse_EnumsOnFieldsAndParameters(t *testing.T) {
+	doc := docFromHTML(t, `<!DOCTYPE html><html><body>
+		<h4>Chat</h4>
+		<table>
+			<thead><tr><th>Field</th><th>Type</th><th>Description</th></tr></thead>
+			<tbody>
+				<tr><td>id</td><td>Integer</td><td>Unique identifier.</td></tr>
+				<tr><td>type</td><td>String</td><td>Type of the chat, can be either “private”, “group”, “supergroup” or “channel”</td></tr>
+			</tbody>
+		</table>
+		<h4>sendDice</h4>
+		<p>Use this method to send an animated emoji.</p>
+		<table>
+			<thead><tr><th>Parameter</th><th>Type</th><th>Required</th><th>Description</th></tr></thead>
+			<tbody>
+				<tr><td>emoji</td><td>String</td><td>Optional</td><td>Currently, must be one of “🎲”, “🎯” or “🏀”</td></tr>
+			</tbody>
+		</table>
+	</body></html>`)
+	page := &PageAPI{Document: doc, Types: make(map[string]Type)}
+	if err := page.LoadTypes(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	fields := page.Types["chat"].Fields
+	if fields[0].Enum != nil {
+		t.Errorf("integer field must not get an enum, got %v", fields[0].Enum)
+	}
+	if want := []string{"private", "group", "supergroup", "channel"}; !reflect.DeepEqual(fields[1].Enum, want) {
+		t.Errorf("Chat.type enum = %v, want %v", fields[1].Enum, want)
+	}
+
+	methods, err := page.GetMethods(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"🎲", "🎯", "🏀"}; !reflect.DeepEqual(methods[0].Parameters[0].Enum, want) {
+		t.Errorf("sendDice.emoji enum = %v, want %v", methods[0].Parameters[0].Enum, want)
+	}
+}
+
+func TestParseConst(t *testing.T) {
+	tests := []struct {
+		desc string
+		want string
+	}{
+		{"The member's status in the chat, always “creator”", "creator"},
+		{"Type of the origin, always “user”", "user"},
+		{"Type of the result, must be “photo”.", "photo"},
+		{"Scope type, must be default", ""},
+		{"Identifier of the query, must be unique", ""},
+		{"Value of the field, must be empty", ""},
+		{"Type of the media, must be “video”", "video"},
+		{"Type of the chat, can be either “private” or “group”", ""},
+		{"Text of the message, must be non-empty if there is no media", ""},
+		{"Unique identifier for the target chat", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.desc, func(t *testing.T) {
+			if got := parseConst(tt.desc); got != tt.want {
+				t.Errorf("parseConst() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadTypes_ConstFields(t *testing.T) {
+	doc := docFromHTML(t, `<!DOCTYPE html><html><body>
+		<h4>BotCommandScopeDefault</h4>
+		<table>
+			<thead><tr><th>Field</th><th>Type</th><th>Description</th></tr></thead>
+			<tbody><tr><td>type</td><td>String</td><td>Scope type, must be <em>default</em></td></tr></tbody>
+		</table>
+	</body></html>`)
+	page := &PageAPI{Document: doc, Types: make(map[string]Type)}
+	if err := page.LoadTypes(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := page.Types["botcommandscopedefault"].Fields[0].Const; got != "default" {
+		t.Errorf("Const = %q, want default", got)
+	}
+	if got := page.Types["botcommandscopedefault"].Fields[0].Description; got != "Scope type, must be default" {
+		t.Errorf("Description = %q, want the text without quotes", got)
+	}
+}
@@ -0,0 +1,23 @@
+package telegram
+
+import (
+	"regexp"
+	"strings"
+)
+
+// int64Re matches the phrasing the docs use for identifiers that don't fit
+// in 32 bits, e.g. "This number may have more than 32 significant bits … a
+// signed 64-bit integer or double-precision float type are safe for storing
+// this identifier."
+var int64Re = regexp.MustCompile(`(?i)more than 32 significant bits|64-bit integer|bigger than 2\^31`)
+
+// is64Bit reports whether an integer-typed element is documented as needing
+// 64 bits.
+func is64Bit(types []string, description string) bool {
+	for _, t := range types {
+		if strings.TrimSpace(stripArrayOf(t)) == "Integer" {
+			return int64Re.MatchString(description)
+		}
+	}
+	return false
+}
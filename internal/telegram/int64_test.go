@@ -0,0 +1,26 @@
+package telegram
+
+import "testing"
+
+func TestIs64Bit(t *testing.T) {
+	const note = "Unique identifier for this chat. This number may have more than 32 significant bits and some programming languages may have difficulty/silent defects in interpreting it. But it has at most 52 significant bits, so a signed 64-bit integer or double-precision float type are safe for storing this identifier."
+	tests := []struct {
+		name  string
+		types []string
+		desc  string
+		want  bool
+	}{
+		{"chat id", []string{"Integer"}, note, true},
+		{"integer or string", []string{"Integer", "String"}, note, true},
+		{"array of integer", []string{"Array of Integer"}, note, true},
+		{"string", []string{"String"}, note, false},
+		{"plain integer", []string{"Integer"}, "Date the message was sent in Unix time", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := is64Bit(tt.types, tt.desc); got != tt.want {
+				t.Errorf("is64Bit() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package telegram
+
+import (
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"golang.org/x/net/html"
+)
+
+// Description formats, see PageAPI.DescriptionFormat.
+const (
+	DescriptionPlain    = "plain"
+	DescriptionMarkdown = "markdown"
+)
+
+// DefaultDocsURL is the page anchors in Markdown descriptions are resolved
+// against when PageAPI.BaseURL is empty.
+const DefaultDocsURL = "https://core.telegram.org/bots/api"
+
+// GatewayDocsURL is the documentation page of the Gateway API.
+const GatewayDocsURL = "https://core.telegram.org/gateway/api"
+
+// description returns the description text of sel in the page's description
+// format. Phrase analysis (enums, constraints, …) always works on the plain
+// text; this is only what gets stored.
+func (p *PageAPI) description(sel *goquery.Selection) string {
+	if p.DescriptionFormat != DescriptionMarkdown {
+		return sel.Text()
+	}
+	return toMarkdown(sel, p.baseURL())
+}
+
+func (p *PageAPI) baseURL() string {
+	if p.BaseURL != "" {
+		return p.BaseURL
+	}
+	return DefaultDocsURL
+}
+
+var (
+	// markdownSpecialRe matches characters that would otherwise start
+	// CommonMark syntax inside text.
+	markdownSpecialRe = regexp.MustCompile("[\\\\`*\\[\\]<>]")
+	// wordUnderscoreRe matches underscores that could open or close
+	// emphasis, i.e. those not inside a word like reply_to_message_id.
+	wordUnderscoreRe = regexp.MustCompile(`(^|[^\p{L}\p{N}])_|_($|[^\p{L}\p{N}])`)
+	blankLinesRe     = regexp.MustCompile(`\n{3,}`)
+)
+
+// toMarkdown converts the contents of sel to CommonMark. Links to anchors and
+// relative pages are made absolute against baseURL; emoji images become
+// their alt text.
+func toMarkdown(sel *goquery.Selection, baseURL string) string {
+	var b strings.Builder
+	for _, n := range sel.Nodes {
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			writeMarkdown(&b, c, baseURL)
+		}
+	}
+	out := blankLinesRe.ReplaceAllString(b.String(), "\n\n")
+	return strings.TrimSpace(out)
+}
+
+func writeMarkdown(b *strings.Builder, n *html.Node, baseURL string) {
+	switch n.Type {
+	case html.TextNode:
+		b.WriteString(escapeMarkdown(n.Data))
+		return
+	case html.ElementNode:
+	default:
+		return
+	}
+
+	children := func() string {
+		var inner strings.Builder
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			writeMarkdown(&inner, c, baseURL)
+		}
+		return inner.String()
+	}
+
+	switch n.Data {
+	case "a":
+		text := children()
+		href := attr(n, "href")
+		if href == "" || strings.TrimSpace(text) == "" {
+			b.WriteString(text)
+			return
+		}
+		b.WriteString("[" + text + "](" + resolveURL(baseURL, href) + ")")
+	case "em", "i":
+		wrapMarkdown(b, "*", children())
+	case "strong", "b":
+		wrapMarkdown(b, "**", children())
+	case "code":
+		code := textOf(n)
+		if strings.Contains(code, "`") {
+			b.WriteString("`` " + code + " ``")
+		} else {
+			b.WriteString("`" + code + "`")
+		}
+	case "pre":
+		b.WriteString("\n\n```\n" + strings.Trim(textOf(n), "\n") + "\n```\n\n")
+	case "br":
+		b.WriteString("  \n")
+	case "img":
+		b.WriteString(attr(n, "alt"))
+	case "p":
+		b.WriteString("\n\n" + strings.TrimSpace(children()) + "\n\n")
+	case "ul", "ol":
+		b.WriteString("\n\n")
+		i := 0
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if c.Type != html.ElementNode || c.Data != "li" {
+				continue
+			}
+			i++
+			marker := "- "
+			if n.Data == "ol" {
+				marker = strconv.Itoa(i) + ". "
+			}
+			var item strings.Builder
+			for cc := c.FirstChild; cc != nil; cc = cc.NextSibling {
+				writeMarkdown(&item, cc, baseURL)
+			}
+			b.WriteString(marker + strings.TrimSpace(item.String()) + "\n")
+		}
+		b.WriteString("\n")
+	case "blockquote":
+		b.WriteString("\n\n")
+		for _, line := range strings.Split(strings.TrimSpace(children()), "\n") {
+			b.WriteString("> " + line + "\n")
+		}
+		b.WriteString("\n")
+	default:
+		b.WriteString(children())
+	}
+}
+
+// wrapMarkdown writes text between delimiters, keeping surrounding spaces
+// outside of them so the emphasis stays valid.
+func wrapMarkdown(b *strings.Builder, delim, text string) {
+	trimmed := strings.TrimSpace(text)
+	if trimmed == "" {
+		b.WriteString(text)
+		return
+	}
+	lead := text[:len(text)-len(strings.TrimLeft(text, " "))]
+	trail := text[len(strings.TrimRight(text, " ")):]
+	b.WriteString(lead + delim + trimmed + delim + trail)
+}
+
+func escapeMarkdown(s string) string {
+	s = markdownSpecialRe.ReplaceAllString(s, `\$0`)
+	return wordUnderscoreRe.ReplaceAllStringFunc(s, func(m string) string {
+		return strings.Replace(m, "_", `\_`, 1)
+	})
+}
+
+// resolveURL makes href absolute against base; anchors ("#message") and
+// site-relative links ("/bots/webapps") both point to core.telegram.org.
+func resolveURL(base, href string) string {
+	b, err := url.Parse(base)
+	if err != nil {
+		return href
+	}
+	ref, err := url.Parse(href)
+	if err != nil {
+		return href
+	}
+	return b.ResolveReference(ref).String()
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+func textOf(n *html.Node) string {
+	return goquery.NewDocumentFromNode(n).Text()
+}
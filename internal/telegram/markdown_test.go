@@ -0,0 +1,93 @@
+package telegram
+
+import (
+	"context"
+	"testing"
+)
+
+func TestToMarkdown(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{
+			name: "anchor link",
+			html: `On success, the sent <a href="#message">Message</a> is returned.`,
+			want: "On success, the sent [Message](https://core.telegram.org/bots/api#message) is returned.",
+		},
+		{
+			name: "relative link",
+			html: `See <a href="/bots/webapps">Web Apps</a>.`,
+			want: "See [Web Apps](https://core.telegram.org/bots/webapps).",
+		},
+		{
+			name: "emphasis and code",
+			html: `Pass <em>True</em> to use <strong>HTML</strong> in <code>parse_mode</code>`,
+			want: "Pass *True* to use **HTML** in `parse_mode`",
+		},
+		{
+			name: "emoji image",
+			html: `Defaults to “<img class="emoji" alt="🎲" src="dice.png">”`,
+			want: "Defaults to “🎲”",
+		},
+		{
+			name: "escaping",
+			html: `Use *bold* or [brackets] in reply_to_message_id and _italic_`,
+			want: `Use \*bold\* or \[brackets\] in reply_to_message_id and \_italic\_`,
+		},
+		{
+			name: "list",
+			html: `<p>It should be one of</p><ul><li><a href="#inputmediaphoto">InputMediaPhoto</a></li><li>InputMediaVideo</li></ul>`,
+			want: "It should be one of\n\n- [InputMediaPhoto](https://core.telegram.org/bots/api#inputmediaphoto)\n- InputMediaVideo",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc := docFromHTML(t, "<html><body><div>"+tt.html+"</div></body></html>")
+			if got := toMarkdown(doc.Find("div"), DefaultDocsURL); got != tt.want {
+				t.Errorf("toMarkdown() =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestGetMethods_MarkdownDescriptions(t *testing.T) {
+	doc := docFromHTML(t, `<!DOCTYPE html><html><body>
+		<h4>sendDice</h4>
+		<p>Use this method to send an animated emoji.</p>
+		<p>On success, the sent <a href="#message">Message</a> is returned.</p>
+		<table>
+			<thead><tr><th>Parameter</th><th>Type</th><th>Required</th><th>Description</th></tr></thead>
+			<tbody>
+				<tr><td>emoji</td><td>String</td><td>Optional</td><td>Must be one of “🎲” or “🎯”. Defaults to “🎲”. See <a href="#dice">Dice</a></td></tr>
+			</tbody>
+		</table>
+	</body></html>`)
+	page := &PageAPI{
+		Document:          doc,
+		Types:             map[string]Type{"message": {Name: "Message"}},
+		DescriptionFormat: DescriptionMarkdown,
+		BaseURL:           "https://core.telegram.org/bots/api",
+	}
+	methods, err := page.GetMethods(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := methods[0]
+	wantDesc := "Use this method to send an animated emoji.\n\nOn success, the sent [Message](https://core.telegram.org/bots/api#message) is returned."
+	if m.Description != wantDesc {
+		t.Errorf("description = %q, want %q", m.Description, wantDesc)
+	}
+	if len(m.ReturnTypes) != 1 || m.ReturnTypes[0].Name != "Message" {
+		t.Errorf("return types = %+v, want Message", m.ReturnTypes)
+	}
+	param := m.Parameters[0]
+	wantParam := "Must be one of “🎲” or “🎯”. Defaults to “🎲”. See [Dice](https://core.telegram.org/bots/api#dice)"
+	if param.Description != wantParam {
+		t.Errorf("parameter description = %q, want %q", param.Description, wantParam)
+	}
+	if param.Default != "🎲" {
+		t.Errorf("phrase analysis must still see the plain text, default = %#v", param.Default)
+	}
+}
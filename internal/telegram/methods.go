@@ -1,101 +1,115 @@
 package telegram
 
 import (
+	"context"
 	"strings"
 	"unicode"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
+// ReturnType is a possible result of a method: a type name (or
+// "boolean"/"integer" for primitive results), optionally wrapped in an array.
 type ReturnType struct {
-	Name    string
-	IsArray bool
+	Name       string `json:"name"`
+	IsArray    bool   `json:"is_array,omitempty"`
+	ArrayDepth int    `json:"array_depth,omitempty"`
 }
 
+// Parameter is an input parameter of a method.
 type Parameter struct {
-	Name        string
-	Type        DataType
-	Description string
-	Required    bool
+	Name        string   `json:"name"`
+	Type        DataType `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Required    bool     `json:"required"`
+	// Enum lists the allowed values of a String parameter, when documented.
+	Enum []string `json:"enum,omitempty"`
+	// Constraints are the documented length, range or size limits.
+	Constraints *Constraints `json:"constraints,omitempty"`
+	// Default is the documented default value ("Defaults to …"), typed
+	// according to the parameter's declared type.
+	Default any `json:"default,omitempty"`
+	// Int64 is set for integers documented as possibly exceeding 32 bits.
+	Int64 bool `json:"int64,omitempty"`
+	// Deprecated is set for parameters the documentation retires;
+	// Replacement names the successor it suggests, if any.
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+	// Since is the API version that introduced the parameter, see
+	// ApplySince.
+	Since string `json:"since,omitempty"`
 }
 
+// Method is a Bot API method from the documentation.
 type Method struct {
-	ReturnType  ReturnType
-	Name        string
-	Description string
-	Parameters  []Parameter
+	Name        string      `json:"name"`
+	Description string      `json:"description,omitempty"`
+	Parameters  []Parameter `json:"parameters,omitempty"`
+	// ReturnTypes lists the possible results, usually one; "the edited
+	// Message is returned, otherwise True" has two. Empty means the
+	// documentation doesn't say.
+	ReturnTypes []ReturnType `json:"return_types,omitempty"`
+	// Section is the <h3> section the method is documented in.
+	Section string `json:"section,omitempty"`
+	// Anchor is the fragment of the method's heading on the page, e.g.
+	// "sendmessage".
+	Anchor string `json:"anchor,omitempty"`
+	// Deprecated is set for methods the documentation retires; Replacement
+	// names the successor it suggests, if any.
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+	// Since is the API version that introduced the method, see ApplySince.
+	Since string `json:"since,omitempty"`
 }
 
-func (p *PageAPI) GetMethods() ([]Method, error) {
+// GetMethods parses the methods of the page in documentation order. It stops
+// with ctx's error once ctx is done.
+func (p *PageAPI) GetMethods(ctx context.Context) ([]Method, error) {
 	var methods []Method
 	var currentMethod Method
+	var section string
 
-	sel := p.Document.Find("h4, table")
+	sel := p.Document.Find("h3, h4, table")
 	for i := range sel.Nodes {
 		s := sel.Eq(i)
 		switch {
+		case s.Is("h3"):
+			if isMethodName(currentMethod.Name) {
+				methods = append(methods, currentMethod)
+			}
+			currentMethod = Method{}
+			section = strings.TrimSpace(s.Text())
 		case s.Is("h4"):
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
 			if isMethodName(currentMethod.Name) {
 				methods = append(methods, currentMethod)
 			}
-			currentMethod = Method{Name: strings.TrimSpace(s.Text())}
+			currentMethod = Method{Name: strings.TrimSpace(s.Text()), Section: section}
+			currentMethod.Anchor = headingAnchor(s, currentMethod.Name)
 
+			var paragraphs []string
 			nextSibling := s.Next()
-			for nextSibling.Length() > 0 && !nextSibling.Is("table") && !nextSibling.Is("h4") {
+			for nextSibling.Length() > 0 && !nextSibling.Is("h3, h4, table") {
 				if nextSibling.Is("p") {
 					currentMethod.Description += nextSibling.Text()
+					paragraphs = append(paragraphs, p.description(nextSibling))
 
-					var fullText string
-					var returnTypeName string
-					var isArray bool
-
-					nextSibling.Contents().Each(func(_ int, s *goquery.Selection) {
-						fullText += s.Text()
-
-						// The documentation phrases array returns as "Array of X"
-						// (capitalized), so match case-insensitively.
-						if strings.Contains(strings.ToLower(s.Text()), "array of") {
-							isArray = true
-						}
-						if goquery.NodeName(s) == "em" {
-							returnTypeName = s.Text()
-							fullText += returnTypeName
-						} else if goquery.NodeName(s) == "a" {
-
-							if href, exists := s.Attr("href"); exists {
-
-								if isFirstLetterUppercase(s.Text()) {
-									i := strings.Index(href, "#")
-									if i != -1 {
-										href = href[i+1:]
-									}
-
-									typeData, err := p.GetType(href)
-									if err == nil {
-										returnTypeName = typeData.Name
-										fullText += returnTypeName
-									}
-								}
-							}
-						}
-					})
-
-					if currentMethod.ReturnType.Name == "" || !isFirstLetterUppercase(currentMethod.ReturnType.Name) {
-						switch returnTypeName {
-						case "True":
-							currentMethod.ReturnType = ReturnType{Name: "boolean", IsArray: isArray}
-						case "Int":
-							currentMethod.ReturnType = ReturnType{Name: "integer", IsArray: isArray}
-						default:
-							typeData, err := p.GetType(strings.ToLower(returnTypeName))
-							if err == nil {
-								currentMethod.ReturnType = ReturnType{Name: typeData.Name, IsArray: isArray}
-							}
+					// A later paragraph may still name the result if this one
+					// only mentioned a primitive.
+					if len(currentMethod.ReturnTypes) == 0 || !isFirstLetterUppercase(currentMethod.ReturnTypes[0].Name) {
+						if rts := p.parseReturnTypes(nextSibling); len(rts) > 0 {
+							currentMethod.ReturnTypes = rts
 						}
 					}
 				}
 				nextSibling = nextSibling.Next()
 			}
+			currentMethod.Deprecated, currentMethod.Replacement = parseDeprecation(currentMethod.Description)
+			if p.DescriptionFormat == DescriptionMarkdown {
+				currentMethod.Description = strings.Join(paragraphs, "\n\n")
+			}
 
 		case s.Is("table"):
 			if currentMethod.Name == "" {
@@ -110,6 +124,7 @@ func (p *PageAPI) GetMethods() ([]Method, error) {
 			s.Find("tbody > tr").Each(func(_ int, tr *goquery.Selection) {
 				var parameter Parameter
 				var isRequired bool
+				var descCell *goquery.Selection
 				tr.Find("td").Each(func(j int, td *goquery.Selection) {
 					header := tr.Parent().Prev().Find("th").Eq(j).Text()
 					switch strings.TrimSpace(header) {
@@ -121,10 +136,21 @@ func (p *PageAPI) GetMethods() ([]Method, error) {
 						isRequired = td.Text() == "Yes"
 					case "Description":
 						parameter.Description = td.Text()
+						descCell = td
 					default:
 					}
 				})
 				parameter.Required = isRequired
+				if isStringType(parameter.Type.Types) {
+					parameter.Enum = parseEnum(parameter.Name, parameter.Description)
+				}
+				parameter.Constraints = p.parseConstraints(currentMethod.Name+"."+parameter.Name, parameter.Description, kindOf(parameter.Type))
+				parameter.Default = parseDefault(parameter.Description, primitiveOf(parameter.Type))
+				parameter.Int64 = is64Bit(parameter.Type.Types, parameter.Description)
+				parameter.Deprecated, parameter.Replacement = parseDeprecation(parameter.Description)
+				if descCell != nil {
+					parameter.Description = p.description(descCell)
+				}
 				currentMethod.Parameters = append(currentMethod.Parameters, parameter)
 			})
 		}
@@ -1,6 +1,7 @@
 package telegram
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -36,7 +37,7 @@ func TestPageAPI_GetMethods(t *testing.T) {
 			"TestType": {Name: "TestType"},
 		},
 	}
-	methods, err := pageAPI.GetMethods()
+	methods, err := pageAPI.GetMethods(context.Background())
 	if err != nil {
 		t.Errorf("GetMethods() error = %v", err)
 	}
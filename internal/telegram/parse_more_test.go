@@ -1,6 +1,7 @@
 package telegram
 
 import (
+	"context"
 	"strings"
 	"testing"
 
@@ -143,7 +144,7 @@ func TestGetTypes_LazyLoads(t *testing.T) {
 		</table>
 	</body></html>`)
 	page := &PageAPI{Document: doc, Types: make(map[string]Type)}
-	types, err := page.GetTypes()
+	types, err := page.GetTypes(context.Background())
 	if err != nil {
 		t.Fatalf("GetTypes() error = %v", err)
 	}
@@ -164,7 +165,7 @@ func TestLoadTypes_RequiredFromOptionalPrefix(t *testing.T) {
 		</table>
 	</body></html>`)
 	page := &PageAPI{Document: doc, Types: make(map[string]Type)}
-	if err := page.LoadTypes(); err != nil {
+	if err := page.LoadTypes(context.Background()); err != nil {
 		t.Fatal(err)
 	}
 	user := page.Types["user"]
@@ -192,7 +193,7 @@ func TestLoadTypes_RequiredColumn(t *testing.T) {
 		</table>
 	</body></html>`)
 	page := &PageAPI{Document: doc, Types: make(map[string]Type)}
-	if err := page.LoadTypes(); err != nil {
+	if err := page.LoadTypes(context.Background()); err != nil {
 		t.Fatal(err)
 	}
 	got := map[string]bool{}
@@ -218,7 +219,7 @@ func TestLoadTypes_UnionAndPlaceholder(t *testing.T) {
 		<p>Just prose, not a type.</p>
 	</body></html>`)
 	page := &PageAPI{Document: doc, Types: make(map[string]Type)}
-	if err := page.LoadTypes(); err != nil {
+	if err := page.LoadTypes(context.Background()); err != nil {
 		t.Fatal(err)
 	}
 
@@ -258,7 +259,7 @@ func TestGetMethods_ReturnTypes(t *testing.T) {
 			"update": {Name: "Update"},
 		},
 	}
-	methods, err := page.GetMethods()
+	methods, err := page.GetMethods(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -267,17 +268,15 @@ func TestGetMethods_ReturnTypes(t *testing.T) {
 		byName[m.Name] = m
 	}
 
-	if rt := byName["getChat"].ReturnType; rt.Name != "Chat" || rt.IsArray {
-		t.Errorf("getChat return = %+v, want {Chat false}", rt)
-	}
-	if rt := byName["getUpdates"].ReturnType; rt.Name != "Update" || !rt.IsArray {
-		t.Errorf("getUpdates return = %+v, want {Update true}", rt)
-	}
-	if rt := byName["deleteMessage"].ReturnType; rt.Name != "boolean" {
-		t.Errorf("deleteMessage return = %+v, want boolean", rt)
-	}
-	if rt := byName["getCount"].ReturnType; rt.Name != "integer" {
-		t.Errorf("getCount return = %+v, want integer", rt)
+	for name, want := range map[string]ReturnType{
+		"getChat":       {Name: "Chat"},
+		"getUpdates":    {Name: "Update", IsArray: true, ArrayDepth: 1},
+		"deleteMessage": {Name: "boolean"},
+		"getCount":      {Name: "integer"},
+	} {
+		if rts := byName[name].ReturnTypes; len(rts) != 1 || rts[0] != want {
+			t.Errorf("%s return = %+v, want %+v", name, rts, want)
+		}
 	}
 }
 
@@ -0,0 +1,116 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrLayoutChanged is wrapped by parsing errors caused by the documentation
+// page not having the structure the parser expects, as opposed to the page
+// being unreachable (see FetchError).
+var ErrLayoutChanged = errors.New("unexpected documentation layout")
+
+// FetchError reports a failure to download the documentation page. StatusCode
+// is zero when the request failed before a response was received, in which
+// case Err holds the underlying network error.
+type FetchError struct {
+	URL        string
+	StatusCode int
+	Status     string
+	Err        error
+}
+
+func (e *FetchError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("failed to fetch %s: status code error: %s", e.URL, e.Status)
+	}
+	return fmt.Sprintf("failed to fetch %s: %v", e.URL, e.Err)
+}
+
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+// Temporary reports whether the request is worth retrying: network errors,
+// rate limiting (429) and server-side (5xx) failures.
+func (e *FetchError) Temporary() bool {
+	return e.StatusCode == 0 ||
+		e.StatusCode == http.StatusTooManyRequests ||
+		e.StatusCode >= 500
+}
+
+// RetryPolicy controls how transient fetch failures are retried. Delays grow
+// exponentially from BaseDelay, are capped at MaxDelay and randomized with
+// jitter; a Retry-After header from the server takes precedence. The zero
+// value disables retries.
+type RetryPolicy struct {
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+}
+
+// DefaultRetryPolicy is used by the CLI unless overridden by flags.
+var DefaultRetryPolicy = RetryPolicy{
+	MaxRetries: 3,
+	BaseDelay:  500 * time.Millisecond,
+	MaxDelay:   30 * time.Second,
+}
+
+// sleep waits for d or until ctx is done. It is swapped out in tests.
+var sleep = func(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// delay returns how long to wait before retry number attempt (starting at 0).
+func (p RetryPolicy) delay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		if p.MaxDelay > 0 && retryAfter > p.MaxDelay {
+			return p.MaxDelay
+		}
+		return retryAfter
+	}
+
+	d := p.BaseDelay << attempt
+	if d <= 0 || (p.MaxDelay > 0 && d > p.MaxDelay) {
+		d = p.MaxDelay
+	}
+	if d <= 0 {
+		return 0
+	}
+	// Jitter into [d/2, d] so concurrent runs don't retry in lockstep.
+	half := d / 2
+	return half + rand.N(d-half+1) //nolint:gosec // jitter doesn't need a CSPRNG
+}
+
+// parseRetryAfter decodes a Retry-After header, given either as a number of
+// seconds or as an HTTP date. It returns zero when the header is absent or
+// malformed.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,185 @@
+package telegram
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// recordSleeps replaces sleep for the duration of the test and returns the
+// slice of requested delays.
+func recordSleeps(t *testing.T) *[]time.Duration {
+	t.Helper()
+	var delays []time.Duration
+	orig := sleep
+	sleep = func(_ context.Context, d time.Duration) error {
+		delays = append(delays, d)
+		return nil
+	}
+	t.Cleanup(func() { sleep = orig })
+	return &delays
+}
+
+func TestHTTPSource_RetriesTransientErrors(t *testing.T) {
+	delays := recordSleeps(t)
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		switch calls {
+		case 1:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case 2:
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			_, _ = w.Write([]byte(sourceTestPage))
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	src := &HTTPSource{URL: srv.URL, Retry: RetryPolicy{MaxRetries: 3, BaseDelay: time.Second, MaxDelay: time.Minute}}
+	if got := readSource(t, src); got != sourceTestPage {
+		t.Errorf("Open() = %q after retries", got)
+	}
+	if calls != 3 {
+		t.Errorf("expected 3 requests, got %d", calls)
+	}
+	if len(*delays) != 2 {
+		t.Fatalf("expected 2 sleeps, got %v", *delays)
+	}
+	if d := (*delays)[0]; d < 500*time.Millisecond || d > time.Second {
+		t.Errorf("first backoff %v outside jitter range [500ms, 1s]", d)
+	}
+	if d := (*delays)[1]; d != 7*time.Second {
+		t.Errorf("second delay = %v, want Retry-After of 7s", d)
+	}
+}
+
+func TestHTTPSource_RetriesExhausted(t *testing.T) {
+	recordSleeps(t)
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := (&HTTPSource{URL: srv.URL, Retry: RetryPolicy{MaxRetries: 2}}).Open(context.Background())
+	var fetchErr *FetchError
+	if !errors.As(err, &fetchErr) {
+		t.Fatalf("expected *FetchError, got %v", err)
+	}
+	if fetchErr.StatusCode != http.StatusBadGateway || fetchErr.URL != srv.URL {
+		t.Errorf("unexpected FetchError: %+v", fetchErr)
+	}
+	if calls != 3 {
+		t.Errorf("expected 1 request + 2 retries, got %d", calls)
+	}
+}
+
+func TestHTTPSource_DoesNotRetryClientErrors(t *testing.T) {
+	delays := recordSleeps(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	t.Cleanup(srv.Close)
+
+	_, err := (&HTTPSource{URL: srv.URL, Retry: DefaultRetryPolicy}).Open(context.Background())
+	var fetchErr *FetchError
+	if !errors.As(err, &fetchErr) || fetchErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected 404 FetchError, got %v", err)
+	}
+	if len(*delays) != 0 {
+		t.Errorf("404 must not be retried, slept %v", *delays)
+	}
+}
+
+func TestHTTPSource_NetworkErrorIsFetchError(t *testing.T) {
+	recordSleeps(t)
+	_, err := (&HTTPSource{URL: "http://127.0.0.1:0", Retry: RetryPolicy{MaxRetries: 1}}).Open(context.Background())
+	var fetchErr *FetchError
+	if !errors.As(err, &fetchErr) {
+		t.Fatalf("expected *FetchError, got %v", err)
+	}
+	if fetchErr.StatusCode != 0 || fetchErr.Unwrap() == nil || !fetchErr.Temporary() {
+		t.Errorf("network FetchError should carry the cause and be temporary: %+v", fetchErr)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		in   string
+		want time.Duration
+	}{
+		{"", 0},
+		{"120", 2 * time.Minute},
+		{"-5", 0},
+		{"Mon, 01 Jan 2024 00:00:30 GMT", 30 * time.Second},
+		{"Sun, 31 Dec 2023 23:00:00 GMT", 0}, // in the past
+		{"soon", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			if got := parseRetryAfter(tt.in, now); got != tt.want {
+				t.Errorf("parseRetryAfter(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRetryPolicy_Delay(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: 4 * time.Second}
+	if d := p.delay(5, 0); d < 2*time.Second || d > 4*time.Second {
+		t.Errorf("delay should be capped at MaxDelay with jitter, got %v", d)
+	}
+	if d := p.delay(0, time.Hour); d != 4*time.Second {
+		t.Errorf("Retry-After should be capped at MaxDelay, got %v", d)
+	}
+	if d := (RetryPolicy{}).delay(0, 0); d != 0 {
+		t.Errorf("zero policy should not wait, got %v", d)
+	}
+}
+
+func TestGetVersion_LayoutError(t *testing.T) {
+	page := &PageAPI{Document: docFromHTML(t, `<html><body></body></html>`)}
+	if _, err := page.GetVersion(); !errors.Is(err, ErrLayoutChanged) {
+		t.Errorf("expected ErrLayoutChanged, got %v", err)
+	}
+}
+
+func TestHTTPSource_CancelStopsRetrying(t *testing.T) {
+	var calls int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	src := &HTTPSource{URL: srv.URL, Retry: RetryPolicy{MaxRetries: 5, BaseDelay: time.Hour}}
+	if _, err := src.Open(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if calls != 1 {
+		t.Errorf("expected a single request before the deadline, got %d", calls)
+	}
+}
+
+func TestHTTPSource_ContextBoundsSlowServer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	src := &HTTPSource{URL: srv.URL, Retry: RetryPolicy{MaxRetries: 5, BaseDelay: time.Millisecond}}
+	if _, err := src.Open(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected context.DeadlineExceeded, got %v", err)
+	}
+}
@@ -0,0 +1,103 @@
+package telegram
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// returnCandidate is a type mentioned in a method description, with its
+// byte offsets in the paragraph text.
+type returnCandidate struct {
+	name       string
+	start, end int
+}
+
+// returnPrimitives maps the primitive names used in return sentences
+// ("<em>True</em> is returned", "Returns <em>Int</em>") to ReturnType names.
+var returnPrimitives = map[string]string{
+	"True":    "boolean",
+	"Boolean": "boolean",
+	"Int":     "integer",
+	"Integer": "integer",
+	"String":  "String",
+}
+
+// parseReturnTypes extracts the return types from a paragraph of a method
+// description. Only the first sentence that talks about returning and
+// mentions a type counts, so links to other types elsewhere in the
+// paragraph are ignored. Every type in that sentence is an alternative:
+// "the edited Message is returned, otherwise True is returned" yields
+// Message or boolean. "Array of" right before a type sets its array depth.
+func (p *PageAPI) parseReturnTypes(paragraph *goquery.Selection) []ReturnType {
+	var text strings.Builder
+	var candidates []returnCandidate
+	paragraph.Contents().Each(func(_ int, s *goquery.Selection) {
+		start := text.Len()
+		text.WriteString(s.Text())
+		if name, ok := p.returnTypeName(s); ok {
+			candidates = append(candidates, returnCandidate{name: name, start: start, end: text.Len()})
+		}
+	})
+	full := text.String()
+
+	for _, sentence := range sentenceBounds(full) {
+		if !strings.Contains(strings.ToLower(full[sentence[0]:sentence[1]]), "return") {
+			continue
+		}
+		var alternatives []ReturnType
+		prevEnd := sentence[0]
+		for _, c := range candidates {
+			if c.start < sentence[0] || c.start >= sentence[1] {
+				continue
+			}
+			depth := strings.Count(strings.ToLower(full[prevEnd:c.start]), "array of")
+			prevEnd = c.end
+			alt := ReturnType{Name: c.name, IsArray: depth > 0, ArrayDepth: depth}
+			if !containsReturnType(alternatives, alt) {
+				alternatives = append(alternatives, alt)
+			}
+		}
+		if len(alternatives) > 0 {
+			return alternatives
+		}
+	}
+	return nil
+}
+
+// returnTypeName returns the ReturnType name of a paragraph node that
+// mentions a type: an emphasized primitive or known type, or a capitalized
+// link to a known type.
+func (p *PageAPI) returnTypeName(s *goquery.Selection) (string, bool) {
+	switch goquery.NodeName(s) {
+	case "em":
+		name := strings.TrimSpace(s.Text())
+		if primitive, ok := returnPrimitives[name]; ok {
+			return primitive, true
+		}
+		if typeData, err := p.GetType(strings.ToLower(name)); err == nil {
+			return typeData.Name, true
+		}
+	case "a":
+		href, exists := s.Attr("href")
+		if !exists || !isFirstLetterUppercase(s.Text()) {
+			return "", false
+		}
+		if i := strings.Index(href, "#"); i != -1 {
+			href = href[i+1:]
+		}
+		if typeData, err := p.GetType(href); err == nil {
+			return typeData.Name, true
+		}
+	}
+	return "", false
+}
+
+func containsReturnType(haystack []ReturnType, needle ReturnType) bool {
+	for _, rt := range haystack {
+		if rt.Name == needle.Name && rt.ArrayDepth == needle.ArrayDepth {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package telegram
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestGetMethods_CompositeReturnTypes(t *testing.T) {
+	doc := docFromHTML(t, `<!DOCTYPE html><html><body>
+		<h4>editMessageText</h4>
+		<p>Use this method to edit text and <a href="#games">game</a> messages. On success, if the edited message is not an inline message, the edited <a href="#message">Message</a> is returned, otherwise <em>True</em> is returned.</p>
+		<h4>sendMediaGroup</h4>
+		<p>Use this method to send a group of photos as an album. On success, an array of <a href="#message">Messages</a> that were sent is returned.</p>
+		<h4>setChatPhoto</h4>
+		<p>Use this method to set a new profile photo for the chat, see <a href="#chat">Chat</a>. Returns <em>True</em> on success.</p>
+		<h4>exportChatInviteLink</h4>
+		<p>Returns the new invite link as <em>String</em> on success.</p>
+	</body></html>`)
+	page := &PageAPI{
+		Document: doc,
+		Types: map[string]Type{
+			"chat":    {Name: "Chat"},
+			"games":   {Name: "Games"},
+			"message": {Name: "Message"},
+		},
+	}
+	methods, err := page.GetMethods(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	byName := map[string][]ReturnType{}
+	for _, m := range methods {
+		byName[m.Name] = m.ReturnTypes
+	}
+
+	want := []ReturnType{{Name: "Message"}, {Name: "boolean"}}
+	if got := byName["editMessageText"]; !reflect.DeepEqual(got, want) {
+		t.Errorf("editMessageText return = %+v, want %+v", got, want)
+	}
+	if got := byName["sendMediaGroup"]; !reflect.DeepEqual(got, []ReturnType{{Name: "Message", IsArray: true, ArrayDepth: 1}}) {
+		t.Errorf("sendMediaGroup return = %+v, want array of Message", got)
+	}
+	if got := byName["setChatPhoto"]; !reflect.DeepEqual(got, []ReturnType{{Name: "boolean"}}) {
+		t.Errorf("setChatPhoto return = %+v, want boolean (Chat is not in the return sentence)", got)
+	}
+	if got := byName["exportChatInviteLink"]; !reflect.DeepEqual(got, []ReturnType{{Name: "String"}}) {
+		t.Errorf("exportChatInviteLink return = %+v, want String", got)
+	}
+}
@@ -0,0 +1,52 @@
+package telegram
+
+import (
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Section is an <h3> section of the documentation page, such as "Available
+// methods" or "Stickers". Types and methods record the section they are
+// documented in.
+type Section struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// GetSections returns the page's sections in document order. The description
+// is the text of the paragraphs between the heading and the first entry of
+// the section.
+func (p *PageAPI) GetSections() []Section {
+	var sections []Section
+	p.Document.Find("h3").Each(func(_ int, h3 *goquery.Selection) {
+		section := Section{Name: strings.TrimSpace(h3.Text())}
+		var paragraphs []string
+		for s := h3.Next(); s.Length() > 0 && !s.Is("h3, h4, table"); s = s.Next() {
+			if s.Is("p") {
+				paragraphs = append(paragraphs, strings.TrimSpace(p.description(s)))
+			}
+		}
+		sep := "\n"
+		if p.DescriptionFormat == DescriptionMarkdown {
+			sep = "\n\n"
+		}
+		section.Description = strings.Join(paragraphs, sep)
+		sections = append(sections, section)
+	})
+	return sections
+}
+
+// headingAnchor returns the anchor of an <h4> heading. The page marks it as
+// <a class="anchor" name="getme" href="#getme">; headings without one fall
+// back to the lowercased name, which is how the page derives anchors.
+func headingAnchor(h4 *goquery.Selection, name string) string {
+	a := h4.Find("a.anchor").First()
+	if anchor := strings.TrimSpace(a.AttrOr("name", "")); anchor != "" {
+		return anchor
+	}
+	if href := a.AttrOr("href", ""); strings.HasPrefix(href, "#") && len(href) > 1 {
+		return href[1:]
+	}
+	return strings.ToLower(name)
+}
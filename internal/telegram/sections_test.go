@@ -0,0 +1,71 @@
+package telegram
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+const sectionsPage = `<!DOCTYPE html><html><body>
+	<h3>Available types</h3>
+	<p>All types used in the Bot API responses are represented as JSON-objects.</p>
+	<h4>User</h4>
+	<p>This object represents a Telegram user or bot.</p>
+	<table>
+		<thead><tr><th>Field</th><th>Type</th><th>Description</th></tr></thead>
+		<tbody><tr><td>id</td><td>Integer</td><td>Unique identifier.</td></tr></tbody>
+	</table>
+	<h3>Available methods</h3>
+	<p>All methods in the Bot API are case-insensitive.</p>
+	<p>They can be called with GET or POST.</p>
+	<h4>getMe</h4>
+	<p>Returns basic information about the bot in form of a <a href="#user">User</a> object.</p>
+	<h3>Stickers</h3>
+	<p>The following methods and objects allow your bot to handle stickers.</p>
+	<h4>Sticker</h4>
+	<p>This object represents a sticker.</p>
+	<table>
+		<thead><tr><th>Field</th><th>Type</th><th>Description</th></tr></thead>
+		<tbody><tr><td>file_id</td><td>String</td><td>Identifier for this file.</td></tr></tbody>
+	</table>
+	<h4>sendSticker</h4>
+	<p>Use this method to send static stickers. On success, the sent <a href="#user">User</a> is returned.</p>
+</body></html>`
+
+func TestGetSections(t *testing.T) {
+	page := &PageAPI{Document: docFromHTML(t, sectionsPage)}
+	want := []Section{
+		{Name: "Available types", Description: "All types used in the Bot API responses are represented as JSON-objects."},
+		{Name: "Available methods", Description: "All methods in the Bot API are case-insensitive.\nThey can be called with GET or POST."},
+		{Name: "Stickers", Description: "The following methods and objects allow your bot to handle stickers."},
+	}
+	if got := page.GetSections(); !reflect.DeepEqual(got, want) {
+		t.Errorf("GetSections() = %+v, want %+v", got, want)
+	}
+}
+
+func TestSectionsOfTypesAndMethods(t *testing.T) {
+	page := &PageAPI{Document: docFromHTML(t, sectionsPage), Types: make(map[string]Type)}
+	if err := page.LoadTypes(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if s := page.Types["user"].Section; s != "Available types" {
+		t.Errorf("User section = %q, want Available types", s)
+	}
+	if s := page.Types["sticker"].Section; s != "Stickers" {
+		t.Errorf("Sticker section = %q, want Stickers", s)
+	}
+
+	methods, err := page.GetMethods(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, m := range methods {
+		got[m.Name] = m.Section
+	}
+	want := map[string]string{"getMe": "Available methods", "sendSticker": "Stickers"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("method sections = %v, want %v", got, want)
+	}
+}
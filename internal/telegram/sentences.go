@@ -0,0 +1,32 @@
+package telegram
+
+// sentenceBounds splits text into sentences, returned as [start, end) byte
+// offsets. A sentence ends at '.', '!' or '?' followed by whitespace or the
+// end of the text.
+func sentenceBounds(text string) [][2]int {
+	var bounds [][2]int
+	start := 0
+	for i := 0; i < len(text); i++ {
+		switch text[i] {
+		case '.', '!', '?':
+			if i+1 == len(text) || text[i+1] == ' ' || text[i+1] == '\n' || text[i+1] == '\t' {
+				bounds = append(bounds, [2]int{start, i + 1})
+				start = i + 1
+			}
+		}
+	}
+	if start < len(text) {
+		bounds = append(bounds, [2]int{start, len(text)})
+	}
+	return bounds
+}
+
+// sentences splits text into sentences as sentenceBounds does.
+func sentences(text string) []string {
+	bounds := sentenceBounds(text)
+	out := make([]string, len(bounds))
+	for i, b := range bounds {
+		out[i] = text[b[0]:b[1]]
+	}
+	return out
+}
@@ -0,0 +1,26 @@
+package telegram
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSentenceBounds(t *testing.T) {
+	text := "Use this method. Returns v1.2 on success! Done"
+	var got []string
+	for _, b := range sentenceBounds(text) {
+		got = append(got, text[b[0]:b[1]])
+	}
+	want := []string{"Use this method.", " Returns v1.2 on success!", " Done"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sentenceBounds() = %q, want %q", got, want)
+	}
+}
+
+func TestSentences(t *testing.T) {
+	got := sentences("Type of the chat. Can be “private”! Or not?")
+	want := []string{"Type of the chat.", " Can be “private”!", " Or not?"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sentences() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,189 @@
+package telegram
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+)
+
+// Addition is an element introduced by a release: the type or method at
+// Anchor, or its field or parameter Name when Name is set.
+type Addition struct {
+	Anchor string `json:"anchor"`
+	Name   string `json:"name,omitempty"`
+}
+
+var (
+	// addedRe matches the phrase that starts a list of new elements, e.g.
+	// "Added the classes " or "Added the optional parameter ".
+	addedRe = regexp.MustCompile(`(?i)\badded\s+(?:the\s+)?(?:new\s+)?(?:optional\s+)?(class(?:es)?|types?|methods?|fields?|parameters?)\s*$`)
+	// addedMoreRe matches a further list in the same sentence, as in "Added
+	// the class Story and the field story to …".
+	addedMoreRe = regexp.MustCompile(`(?i)^\s*,?\s*(?:and\s+)?(?:the\s+)?(?:new\s+)?(?:optional\s+)?(class(?:es)?|types?|methods?|fields?|parameters?)\s*$`)
+	// listSeparatorRe matches the text between the items of such a list.
+	listSeparatorRe = regexp.MustCompile(`^\s*,?\s*(?:and\s*)?$`)
+	// sentenceEndRe matches the end of a sentence inside a bullet.
+	sentenceEndRe = regexp.MustCompile(`\.(?:\s|$)`)
+)
+
+// changeToken is a piece of a change bullet: plain text, a link to an
+// element of the page, or an emphasized field or parameter name.
+type changeToken struct {
+	text, anchor, name string
+}
+
+// parseAdditions returns the elements a change bullet introduces. A phrase
+// like "Added the classes" is followed by links to the new types or
+// methods; "Added the field" (or parameter) is followed by their names in
+// <em> and then by links to the types or methods that gained them. Only
+// the list of links right after the names gets them: other text between
+// links, or the end of the sentence, ends the phrase.
+func (p *PageAPI) parseAdditions(li *goquery.Selection) []Addition {
+	var tokens []changeToken
+	li.Contents().Each(func(_ int, s *goquery.Selection) {
+		switch goquery.NodeName(s) {
+		case "a":
+			if anchor, ok := p.pageAnchor(s.AttrOr("href", "")); ok {
+				tokens = append(tokens, changeToken{anchor: anchor})
+				return
+			}
+		case "em", "i", "code":
+			tokens = append(tokens, changeToken{name: strings.TrimSpace(s.Text())})
+			return
+		}
+		tokens = append(tokens, changeToken{text: s.Text()})
+	})
+
+	var added []Addition
+	var kind string
+	var names []string
+	collecting, targeting := false, false
+	for _, tok := range tokens {
+		switch {
+		case tok.anchor != "":
+			switch {
+			case collecting && kind == "element":
+				added = append(added, Addition{Anchor: tok.anchor})
+				continue
+			case kind == "member":
+				for _, name := range names {
+					added = append(added, Addition{Anchor: tok.anchor, Name: name})
+				}
+				targeting = true
+			}
+		case tok.name != "":
+			if collecting && kind == "member" {
+				names = append(names, tok.name)
+				continue
+			}
+		default:
+			m := addedRe.FindStringSubmatch(tok.text)
+			if m == nil && kind != "" {
+				m = addedMoreRe.FindStringSubmatch(tok.text)
+			}
+			if m != nil {
+				kind, names, collecting, targeting = "element", nil, true, false
+				if w := strings.ToLower(m[1]); strings.HasPrefix(w, "field") || strings.HasPrefix(w, "parameter") {
+					kind = "member"
+				}
+				continue
+			}
+			separator := listSeparatorRe.MatchString(tok.text)
+			if collecting && separator {
+				continue
+			}
+			if kind == "member" && (targeting && !separator || sentenceEndRe.MatchString(tok.text)) {
+				kind, names, targeting = "", nil, false
+			}
+		}
+		collecting = false
+	}
+	return added
+}
+
+// ApplySince sets Since on the types, methods, fields and parameters that
+// releases introduce, keeping the oldest release for elements mentioned
+// more than once. Fields and parameters of a new type or method inherit its
+// version. Elements that predate every listed release keep an empty Since.
+func ApplySince(types map[string]Type, methods []Method, releases []Release) {
+	typeKeys := make(map[string]string, len(types))
+	for key, t := range types {
+		typeKeys[anchorOf(t.Anchor, t.Name)] = key
+	}
+	methodIndex := make(map[string]int, len(methods))
+	for i, m := range methods {
+		methodIndex[anchorOf(m.Anchor, m.Name)] = i
+	}
+
+	// Releases are listed newest first; walk them oldest first so the first
+	// version seen for an element is the one that introduced it.
+	for i := len(releases) - 1; i >= 0; i-- {
+		version := releases[i].Version
+		for _, c := range releases[i].Changes {
+			for _, a := range c.Added {
+				if key, ok := typeKeys[a.Anchor]; ok {
+					t := types[key]
+					stampType(&t, a.Name, version)
+					types[key] = t
+				}
+				if j, ok := methodIndex[a.Anchor]; ok {
+					stampMethod(&methods[j], a.Name, version)
+				}
+			}
+		}
+	}
+
+	for key, t := range types {
+		for j := range t.Fields {
+			if t.Fields[j].Since == "" {
+				t.Fields[j].Since = t.Since
+			}
+		}
+		types[key] = t
+	}
+	for i := range methods {
+		for j := range methods[i].Parameters {
+			if methods[i].Parameters[j].Since == "" {
+				methods[i].Parameters[j].Since = methods[i].Since
+			}
+		}
+	}
+}
+
+func stampType(t *Type, field, version string) {
+	if field == "" {
+		if t.Since == "" {
+			t.Since = version
+		}
+		return
+	}
+	for j := range t.Fields {
+		if t.Fields[j].Name == field && t.Fields[j].Since == "" {
+			t.Fields[j].Since = version
+		}
+	}
+}
+
+func stampMethod(m *Method, parameter, version string) {
+	if parameter == "" {
+		if m.Since == "" {
+			m.Since = version
+		}
+		return
+	}
+	for j := range m.Parameters {
+		if m.Parameters[j].Name == parameter && m.Parameters[j].Since == "" {
+			m.Parameters[j].Since = version
+		}
+	}
+}
+
+// anchorOf returns the anchor of an element, falling back to its lowercased
+// name for elements parsed before anchors were recorded.
+func anchorOf(anchor, name string) string {
+	if anchor != "" {
+		return anchor
+	}
+	return strings.ToLower(name)
+}
@@ -0,0 +1,115 @@
+package telegram
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAdditions(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want []Addition
+	}{
+		{
+			name: "classes",
+			html: `Added the classes <a href="#chatboost">ChatBoost</a>, <a href="#chatboostsource">ChatBoostSource</a> and <a href="#userchatboosts">UserChatBoosts</a>.`,
+			want: []Addition{{Anchor: "chatboost"}, {Anchor: "chatboostsource"}, {Anchor: "userchatboosts"}},
+		},
+		{
+			name: "method followed by unrelated links",
+			html: `Added the method <a href="#getuserchatboosts">getUserChatBoosts</a>, allowing bots to get the boosts of a <a href="#user">User</a>.`,
+			want: []Addition{{Anchor: "getuserchatboosts"}},
+		},
+		{
+			name: "parameter of several methods",
+			html: `Added the parameter <em>business_connection_id</em> to the methods <a href="#sendmessage">sendMessage</a> and <a href="#sendphoto">sendPhoto</a>.`,
+			want: []Addition{
+				{Anchor: "sendmessage", Name: "business_connection_id"},
+				{Anchor: "sendphoto", Name: "business_connection_id"},
+			},
+		},
+		{
+			name: "several fields",
+			html: `Added the fields <em>can_post_stories</em> and <em>can_edit_stories</em> to the class <a href="#chatadministratorrights">ChatAdministratorRights</a>.`,
+			want: []Addition{
+				{Anchor: "chatadministratorrights", Name: "can_post_stories"},
+				{Anchor: "chatadministratorrights", Name: "can_edit_stories"},
+			},
+		},
+		{
+			name: "class and field in one bullet",
+			html: `Added the class <a href="#story">Story</a> and the field <em>story</em> to the class <a href="#message">Message</a>.`,
+			want: []Addition{{Anchor: "story"}, {Anchor: "message", Name: "story"}},
+		},
+		{
+			name: "field followed by unrelated links",
+			html: `Added the field <em>gift</em> to the class <a href="#message">Message</a>, which can be sent using <a href="#sendgift">sendGift</a>. See <a href="#gift">Gift</a> for details.`,
+			want: []Addition{{Anchor: "message", Name: "gift"}},
+		},
+		{
+			name: "field and method in one bullet",
+			html: `Added the parameter <em>pay_for_upgrade</em> to the method <a href="#sendgift">sendGift</a>. Added the method <a href="#upgradegift">upgradeGift</a>.`,
+			want: []Addition{{Anchor: "sendgift", Name: "pay_for_upgrade"}, {Anchor: "upgradegift"}},
+		},
+		{
+			name: "names without targets end with the sentence",
+			html: `Added the field <em>gift</em>. Supported in <a href="#message">Message</a>.`,
+		},
+		{
+			name: "links to other pages are ignored",
+			html: `Added the method <a href="/bots/webapps#initializing-mini-apps">Mini Apps</a>.`,
+		},
+		{
+			name: "no addition",
+			html: `Increased the maximum size of <a href="#message">Message</a> text.`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			page := &PageAPI{Document: docFromHTML(t, "<html><body><ul><li>"+tt.html+"</li></ul></body></html>")}
+			got := page.parseAdditions(page.Document.Find("li"))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("parseAdditions() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestApplySince(t *testing.T) {
+	types := map[string]Type{
+		"message": {Name: "Message", Anchor: "message", Fields: []Field{{Name: "message_id"}, {Name: "story"}}},
+		"story":   {Name: "Story", Anchor: "story", Fields: []Field{{Name: "id"}, {Name: "chat"}}},
+	}
+	methods := []Method{
+		{Name: "sendMessage", Anchor: "sendmessage", Parameters: []Parameter{{Name: "text"}, {Name: "business_connection_id"}}},
+		{Name: "getStory", Anchor: "getstory", Parameters: []Parameter{{Name: "story_id"}}},
+	}
+	releases := []Release{
+		{Version: "7.2", Changes: []Change{
+			{Added: []Addition{{Anchor: "sendmessage", Name: "business_connection_id"}, {Anchor: "getstory"}}},
+			// A later mention must not override the first version.
+			{Added: []Addition{{Anchor: "story"}}},
+		}},
+		{Version: "7.0", Changes: []Change{
+			{Added: []Addition{{Anchor: "story"}, {Anchor: "message", Name: "story"}, {Anchor: "unknown"}}},
+		}},
+	}
+	ApplySince(types, methods, releases)
+
+	if got := types["story"].Since; got != "7.0" {
+		t.Errorf("Story since = %q, want 7.0", got)
+	}
+	if got := types["story"].Fields[1].Since; got != "7.0" {
+		t.Errorf("fields of a new type must inherit its version, got %q", got)
+	}
+	if got := types["message"]; got.Since != "" || got.Fields[0].Since != "" || got.Fields[1].Since != "7.0" {
+		t.Errorf("Message = %+v, want only the story field since 7.0", got)
+	}
+	if got := methods[0]; got.Since != "" || got.Parameters[0].Since != "" || got.Parameters[1].Since != "7.2" {
+		t.Errorf("sendMessage = %+v, want only business_connection_id since 7.2", got)
+	}
+	if got := methods[1]; got.Since != "7.2" || got.Parameters[0].Since != "7.2" {
+		t.Errorf("getStory = %+v, want the method and its parameters since 7.2", got)
+	}
+}
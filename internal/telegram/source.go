@@ -0,0 +1,55 @@
+package telegram
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+)
+
+// Source supplies the raw HTML of a documentation page. It lets callers parse
+// the docs without caring whether they came from the network, a saved copy on
+// disk or standard input.
+type Source interface {
+	fmt.Stringer
+	// Open returns a reader over the page HTML. The caller must close it.
+	Open(ctx context.Context) (io.ReadCloser, error)
+}
+
+// FileSource reads the documentation page from a local HTML file.
+type FileSource struct {
+	Path string
+}
+
+func (s *FileSource) String() string {
+	return s.Path
+}
+
+func (s *FileSource) Open(ctx context.Context) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	f, err := os.Open(s.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open input file: %w", err)
+	}
+	return f, nil
+}
+
+// ReaderSource reads the documentation page from an arbitrary reader such as
+// standard input. Name is only used to describe the source in logs.
+type ReaderSource struct {
+	Name   string
+	Reader io.Reader
+}
+
+func (s *ReaderSource) String() string {
+	return s.Name
+}
+
+func (s *ReaderSource) Open(ctx context.Context) (io.ReadCloser, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return io.NopCloser(s.Reader), nil
+}
@@ -0,0 +1,47 @@
+package telegram
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const sourceTestPage = `<!DOCTYPE html><html><body><strong>Bot API 7.0</strong></body></html>`
+
+func TestLoadPage_FileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "api.html")
+	if err := os.WriteFile(path, []byte(sourceTestPage), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	page, err := LoadPage(context.Background(), &FileSource{Path: path})
+	if err != nil {
+		t.Fatalf("LoadPage() error = %v", err)
+	}
+	if v, err := page.GetVersion(); err != nil || v != "7.0" {
+		t.Errorf("GetVersion() = %q, %v; want 7.0", v, err)
+	}
+}
+
+func TestLoadPage_FileSourceMissing(t *testing.T) {
+	src := &FileSource{Path: filepath.Join(t.TempDir(), "missing.html")}
+	if _, err := LoadPage(context.Background(), src); err == nil {
+		t.Error("LoadPage() with a missing file should return error")
+	}
+}
+
+func TestLoadPage_ReaderSource(t *testing.T) {
+	src := &ReaderSource{Name: "stdin", Reader: strings.NewReader(sourceTestPage)}
+	if src.String() != "stdin" {
+		t.Errorf("String() = %q, want stdin", src.String())
+	}
+	page, err := LoadPage(context.Background(), src)
+	if err != nil {
+		t.Fatalf("LoadPage() error = %v", err)
+	}
+	if page.Types == nil {
+		t.Error("NewPage() should initialize the types map")
+	}
+}
@@ -1,23 +1,53 @@
 package telegram
 
 import (
+	"context"
 	"fmt"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
 )
 
+// Field is a field of an object type. Type holds the declared type
+// alternatives verbatim, e.g. ["Integer", "String"] or ["Array of PhotoSize"].
 type Field struct {
-	Name        string
-	Type        []string
-	Description string
-	Required    bool
+	Name        string   `json:"name"`
+	Type        []string `json:"type"`
+	Description string   `json:"description,omitempty"`
+	Required    bool     `json:"required"`
+	// Enum lists the allowed values of a String field, when documented.
+	Enum []string `json:"enum,omitempty"`
+	// Const is the fixed value of a String field, e.g. the "type" of a union
+	// variant that is documented as always “photo”.
+	Const string `json:"const,omitempty"`
+	// Constraints are the documented length, range or size limits.
+	Constraints *Constraints `json:"constraints,omitempty"`
+	// Default is the documented default value ("Defaults to …"), typed
+	// according to the field's declared type.
+	Default any `json:"default,omitempty"`
+	// Int64 is set for integers documented as possibly exceeding 32 bits.
+	Int64 bool `json:"int64,omitempty"`
+	// Deprecated is set for fields the documentation retires; Replacement
+	// names the successor it suggests, if any.
+	Deprecated  bool   `json:"deprecated,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+	// Since is the API version that introduced the field, see ApplySince.
+	Since string `json:"since,omitempty"`
 }
 
+// Type is an object type from the documentation. Union types have no fields;
+// their variants are listed in the description as "- Variant" lines.
 type Type struct {
-	Name        string
-	Description string
-	Fields      []Field
+	Name        string  `json:"name"`
+	Description string  `json:"description,omitempty"`
+	Fields      []Field `json:"fields,omitempty"`
+	// Section is the <h3> section the type is documented in.
+	Section string `json:"section,omitempty"`
+	// Anchor is the fragment of the type's heading on the page, e.g.
+	// "message" for https://core.telegram.org/bots/api#message.
+	Anchor string `json:"anchor,omitempty"`
+	// Since is the API version that introduced the type, see ApplySince.
+	Since string `json:"since,omitempty"`
 }
 
 func (p *PageAPI) GetType(name string) (Type, error) {
@@ -31,33 +61,47 @@ func (p *PageAPI) GetType(name string) (Type, error) {
 	return Type{}, fmt.Errorf("type %s not found", name)
 }
 
-func (p *PageAPI) GetTypes() (map[string]Type, error) {
+// GetTypes returns the types of the page, loading them on first use.
+func (p *PageAPI) GetTypes(ctx context.Context) (map[string]Type, error) {
 	if len(p.Types) == 0 {
-		if err := p.LoadTypes(); err != nil {
+		if err := p.LoadTypes(ctx); err != nil {
 			return nil, fmt.Errorf("failed to load types: %w", err)
 		}
 	}
 	return p.Types, nil
 }
 
-func (p *PageAPI) LoadTypes() error {
+// LoadTypes parses the type definitions of the page into p.Types. It stops
+// with ctx's error once ctx is done.
+func (p *PageAPI) LoadTypes(ctx context.Context) error {
 	var types []Type
 	var currentType Type
+	var section string
 
-	sel := p.Document.Find("h4, table")
+	sel := p.Document.Find("h3, h4, table")
 	for i := range sel.Nodes {
 		s := sel.Eq(i)
 		switch {
+		case s.Is("h3"):
+			if shouldKeepType(currentType) {
+				types = append(types, currentType)
+			}
+			currentType = Type{}
+			section = strings.TrimSpace(s.Text())
 		case s.Is("h4"):
+			if err := ctx.Err(); err != nil {
+				return err
+			}
 			if shouldKeepType(currentType) {
 				types = append(types, currentType)
 			}
-			currentType = Type{Name: strings.TrimSpace(s.Text())}
+			currentType = Type{Name: strings.TrimSpace(s.Text()), Section: section}
+			currentType.Anchor = headingAnchor(s, currentType.Name)
 
 			nextSibling := s.Next()
-			for nextSibling.Length() > 0 && !nextSibling.Is("table") && !nextSibling.Is("h4") {
+			for nextSibling.Length() > 0 && !nextSibling.Is("h3, h4, table") {
 				if nextSibling.Is("p") {
-					currentType.Description += nextSibling.Text() + "\n"
+					currentType.Description += p.description(nextSibling) + "\n"
 					ul := nextSibling.Next()
 					if ul.Is("ul") {
 						ul.Find("li a").Each(func(_ int, a *goquery.Selection) {
@@ -83,6 +127,7 @@ func (p *PageAPI) LoadTypes() error {
 				var field Field
 				var isRequired bool
 				var hasRequiredColumn bool
+				var descCell *goquery.Selection
 				tr.Find("td").Each(func(j int, td *goquery.Selection) {
 					header := tr.Parent().Prev().Find("th").Eq(j).Text()
 					switch strings.TrimSpace(header) {
@@ -99,6 +144,7 @@ func (p *PageAPI) LoadTypes() error {
 						isRequired = strings.TrimSpace(td.Text()) == "Yes"
 					case "Description":
 						field.Description = td.Text()
+						descCell = td
 					default:
 					}
 				})
@@ -110,6 +156,20 @@ func (p *PageAPI) LoadTypes() error {
 				} else {
 					field.Required = !strings.HasPrefix(strings.TrimSpace(field.Description), "Optional")
 				}
+				if isStringType(field.Type) {
+					field.Enum = parseEnum(field.Name, field.Description)
+					if descCell != nil {
+						field.Const = parseConst(quoteEmphasis(descCell))
+					}
+				}
+				dataType := fieldDataType(field.Type)
+				field.Constraints = p.parseConstraints(currentType.Name+"."+field.Name, field.Description, kindOf(dataType))
+				field.Default = parseDefault(field.Description, primitiveOf(dataType))
+				field.Int64 = is64Bit(field.Type, field.Description)
+				field.Deprecated, field.Replacement = parseDeprecation(field.Description)
+				if descCell != nil {
+					field.Description = p.description(descCell)
+				}
 				currentType.Fields = append(currentType.Fields, field)
 			})
 		}
@@ -1,6 +1,8 @@
 package telegram
 
 import (
+	"context"
+	"errors"
 	"strings"
 	"testing"
 
@@ -113,7 +115,7 @@ func TestPageAPI_LoadTypes(t *testing.T) {
 		Types:    make(map[string]Type),
 	}
 
-	if err := pageAPI.LoadTypes(); err != nil {
+	if err := pageAPI.LoadTypes(context.Background()); err != nil {
 		t.Errorf("PageAPI.LoadTypes() error = %v", err)
 		return
 	}
@@ -123,3 +125,25 @@ func TestPageAPI_LoadTypes(t *testing.T) {
 		t.Errorf("Expected 2 types loaded, got %d", len(pageAPI.Types))
 	}
 }
+
+func TestPageAPI_Cancelled(t *testing.T) {
+	doc := docFromHTML(t, `<!DOCTYPE html><html><body>
+		<h4>User</h4>
+		<table>
+			<thead><tr><th>Field</th><th>Type</th><th>Description</th></tr></thead>
+			<tbody><tr><td>id</td><td>Integer</td><td>Unique identifier</td></tr></tbody>
+		</table>
+		<h4>getMe</h4>
+		<p>Returns basic information about the bot.</p>
+	</body></html>`)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	page := &PageAPI{Document: doc, Types: make(map[string]Type)}
+	if _, err := page.GetTypes(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetTypes() error = %v, want context.Canceled", err)
+	}
+	if _, err := page.GetMethods(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetMethods() error = %v, want context.Canceled", err)
+	}
+}
@@ -1,7 +1,7 @@
 package telegram
 
 import (
-	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/PuerkitoBio/goquery"
@@ -37,10 +37,10 @@ func (p *PageAPI) GetVersion() (string, error) {
 	})
 
 	if !foundRecentChanges {
-		return "", errors.New("can't find 'Recent Changes' section")
+		return "", fmt.Errorf("%w: can't find 'Recent Changes' section", ErrLayoutChanged)
 	}
 	if version == "" {
-		return "", errors.New("can't find <h4> tag after 'Recent Changes'")
+		return "", fmt.Errorf("%w: can't find <h4> tag after 'Recent Changes'", ErrLayoutChanged)
 	}
 	return version, nil
 }